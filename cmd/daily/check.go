@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/config"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/linkcheck"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// runCheck проверяет ссылки хранилища без конвертации; при битых ссылках и якорях
+// возвращает ошибку, чтобы процесс завершился с ненулевым кодом (для pre-commit).
+// Заметки без входящих ссылок выводятся только как предупреждения
+func runCheck(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	format := fs.String("format", linkcheck.FormatText, "Формат отчёта: text или json")
+	outPath := fs.String("out", "", "Файл для отчёта (по умолчанию stdout)")
+	orphans := fs.Bool("orphans", false, "Предупреждать о заметках без входящих ссылок")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	absSrcDir, err := filepath.Abs(cfg.SrcDir)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Проверка ссылок в заметках из %s", absSrcDir)
+
+	vaultOpts, err := vaultOptions(cfg)
+	if err != nil {
+		return err
+	}
+	v, err := vault.Load(absSrcDir, vaultOpts...)
+	if err != nil {
+		return err
+	}
+
+	problems, err := linkcheck.Check(v, linkcheck.Options{Orphans: *orphans})
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*outPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := linkcheck.Write(out, problems, *format); err != nil {
+		return err
+	}
+	errors := 0
+	for _, p := range problems {
+		if !p.IsWarning() {
+			errors++
+		}
+	}
+	if errors > 0 {
+		return fmt.Errorf("найдено проблем: %d", errors)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/config"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/converter"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/sitemap"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+func runCommand(cfg *config.Config, command string, args []string) error {
+	switch command {
+	case "stats":
+		return runStats(cfg, args)
+	case "daily":
+		return runDaily(cfg, args)
+	case "export":
+		return runExport(cfg, args)
+	case "sqlite":
+		return runSQLite(cfg, args)
+	case "check":
+		return runCheck(cfg, args)
+	case "validate":
+		return runValidate(cfg, args)
+	case "fix":
+		return runFix(cfg, args)
+	default:
+		return fmt.Errorf("неизвестная команда: %s", command)
+	}
+}
+
+// newConverter создаёт конвертер с настройками рендеринга из конфигурации
+func newConverter(cfg *config.Config) (*converter.Converter, error) {
+	renderer, err := converter.NewRenderer(cfg.Renderer)
+	if err != nil {
+		return nil, err
+	}
+	highlight := converter.HighlightOptions{Theme: cfg.Highlight.Theme, LineNumbers: cfg.Highlight.LineNumbers}
+	if highlight.Theme == "" {
+		highlight.Theme = converter.DefaultHighlightTheme
+	}
+	mermaid := converter.MermaidOptions{Mode: cfg.Mermaid.Mode, Script: cfg.Mermaid.Script, CLI: cfg.Mermaid.CLI}
+	vaultOpts, err := vaultOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	robots := make([]sitemap.RobotsRule, 0, len(cfg.Robots.Rules))
+	for _, r := range cfg.Robots.Rules {
+		robots = append(robots, sitemap.RobotsRule{UserAgent: r.UserAgent, Allow: r.Allow, Disallow: r.Disallow})
+	}
+	return converter.NewConverter(
+		converter.WithRenderer(renderer),
+		converter.WithHighlight(highlight),
+		converter.WithMermaid(mermaid),
+		converter.WithTOC(converter.TOCOptions{MinHeadings: cfg.TOC.MinHeadings}),
+		converter.WithURLs(converter.URLOptions{Style: cfg.URLs.Style}),
+		converter.WithVaultOptions(vaultOpts...),
+		converter.WithSite(converter.SiteOptions{
+			BaseURL:     cfg.Site.BaseURL,
+			Title:       cfg.Site.Title,
+			Description: cfg.Site.Description,
+			Author:      cfg.Author,
+		}),
+		converter.WithFeeds(converter.FeedOptions{
+			Enabled: cfg.Feeds.Enabled,
+			Limit:   cfg.Feeds.Limit,
+			Summary: cfg.Feeds.Content == "summary",
+			Tags:    cfg.Feeds.Tags,
+		}),
+		converter.WithSitemap(converter.SitemapOptions{
+			Enabled:     cfg.Sitemap.Enabled,
+			Robots:      cfg.Robots.Enabled,
+			RobotsRules: robots,
+		}),
+	), nil
+}
+
+// vaultOptions возвращает настройки разбора заметок: часовой пояс и запасные источники даты
+func vaultOptions(cfg *config.Config) ([]vault.Option, error) {
+	loc, err := location(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return []vault.Option{vault.WithLocation(loc), vault.WithDateFallback(cfg.Dates.Fallback...)}, nil
+}
+
+// location возвращает часовой пояс дат из конфигурации; по умолчанию UTC
+func location(cfg *config.Config) (*time.Location, error) {
+	if cfg.Dates.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(cfg.Dates.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("неизвестный часовой пояс %s: %v", cfg.Dates.Timezone, err)
+	}
+	return loc, nil
+}
+
+// openOutput возвращает файл для записи результата или stdout, если путь не задан
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать файл %s: %v", path, err)
+	}
+	return f, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/config"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/timeseries"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func runExport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", timeseries.FormatCSV, "Формат: csv, jsonl или sqlite")
+	outPath := fs.String("out", "", "Файл для результата (для sqlite обязателен, иначе по умолчанию stdout)")
+	skipMissing := fs.Bool("skip-missing", false, "Не добавлять пустые строки за дни без заметок")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	absSrcDir, err := filepath.Abs(cfg.SrcDir)
+	if err != nil {
+		return err
+	}
+
+	vaultOpts, err := vaultOptions(cfg)
+	if err != nil {
+		return err
+	}
+	v, err := vault.Load(absSrcDir, vaultOpts...)
+	if err != nil {
+		return err
+	}
+
+	table := timeseries.Build(v, !*skipMissing)
+	log.Infof("Экспорт полей %v за %d дн.", table.Fields, len(table.Rows))
+
+	if *format == timeseries.FormatSQLite {
+		if *outPath == "" {
+			return fmt.Errorf("для формата sqlite нужно указать -out")
+		}
+		return timeseries.WriteSQLite(*outPath, table)
+	}
+
+	out, err := openOutput(*outPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	switch *format {
+	case timeseries.FormatCSV:
+		return timeseries.WriteCSV(out, table)
+	case timeseries.FormatJSONL:
+		return timeseries.WriteJSONL(out, table)
+	default:
+		return fmt.Errorf("неизвестный формат экспорта: %s", *format)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/config"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/fixer"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// runFix нормализует FrontMatter заметок в src_dir. Без -write только выводит diff
+func runFix(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("fix", flag.ExitOnError)
+	write := fs.Bool("write", false, "Записать исправления в файлы заметок")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	absSrcDir, err := filepath.Abs(cfg.SrcDir)
+	if err != nil {
+		return err
+	}
+	loc, err := location(cfg)
+	if err != nil {
+		return err
+	}
+
+	changes, err := fixer.FixDir(absSrcDir, fixer.Options{KeyOrder: cfg.Fix.KeyOrder, Location: loc})
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		fmt.Print(fixer.Diff(change.RelPath, change.Before, change.After))
+	}
+	if len(changes) == 0 {
+		log.Info("FrontMatter всех заметок уже нормализован")
+		return nil
+	}
+	if !*write {
+		log.Infof("Нужно исправить заметок: %d. Запустите fix -write, чтобы записать изменения", len(changes))
+		return nil
+	}
+
+	for _, change := range changes {
+		info, err := os.Stat(change.Path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(change.Path, change.After, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("не удалось записать файл %s: %v", change.Path, err)
+		}
+	}
+	log.Infof("Исправлено заметок: %d", len(changes))
+	return nil
+}
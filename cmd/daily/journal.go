@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/config"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/journal"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+func runDaily(cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "new" {
+		return fmt.Errorf("использование: daily new [-date YYYY-MM-DD] [-close-previous]")
+	}
+
+	fs := flag.NewFlagSet("daily new", flag.ExitOnError)
+	dateFlag := fs.String("date", "", "Дата заметки в формате YYYY-MM-DD (по умолчанию сегодня)")
+	closePrevious := fs.Bool("close-previous", false, "Пометить предыдущую заметку closed: true, если после переноса в ней не осталось открытых задач")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	// «Сегодня» считается в часовом поясе заметок, а не хоста
+	loc, err := location(cfg)
+	if err != nil {
+		return err
+	}
+	date := time.Now().In(loc)
+	if *dateFlag != "" {
+		parsed, err := time.ParseInLocation(vault.DateLayout, *dateFlag, loc)
+		if err != nil {
+			return fmt.Errorf("некорректная дата %q: %v", *dateFlag, err)
+		}
+		date = parsed
+	}
+
+	var templateText string
+	if cfg.DailyTemplate != "" {
+		content, err := os.ReadFile(cfg.DailyTemplate)
+		if err != nil {
+			return fmt.Errorf("не удалось прочитать шаблон ежедневной заметки: %v", err)
+		}
+		templateText = string(content)
+	}
+
+	absSrcDir, err := filepath.Abs(cfg.SrcDir)
+	if err != nil {
+		return err
+	}
+
+	vaultOpts, err := vaultOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	j, err := journal.NewJournal(absSrcDir, cfg.Author, templateText, vaultOpts...)
+	if err != nil {
+		return err
+	}
+
+	notePath, err := j.NewNote(date, *closePrevious)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(notePath)
+	return nil
+}
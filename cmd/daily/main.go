@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 
 	"github.com/ANkulagin/golang_markdown_converter_sb/internal/config"
-	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/converter"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -36,6 +35,15 @@ func main() {
 	// Настройка вывода логов (можно перенаправить в файл, если нужно)
 	log.SetOutput(os.Stdout)
 
+	// Подкоманды пишут результат в stdout, поэтому их логи уходят в stderr
+	if command := flag.Arg(0); command != "" {
+		log.SetOutput(os.Stderr)
+		if err := runCommand(cfg, command, flag.Args()[1:]); err != nil {
+			log.Fatalf("Команда %s завершилась с ошибкой: %v", command, err)
+		}
+		return
+	}
+
 	// Преобразование относительных путей в абсолютные
 	absSrcDir, err := filepath.Abs(cfg.SrcDir)
 	if err != nil {
@@ -50,7 +58,10 @@ func main() {
 	log.Infof("Конвертация заметок из %s в %s", absSrcDir, absDestDir)
 	log.Infof("Уровень логирования: %s", cfg.LogLevel)
 
-	conv := converter.NewConverter()
+	conv, err := newConverter(cfg)
+	if err != nil {
+		log.Fatalf("Не удалось создать конвертер: %v", err)
+	}
 
 	if err := conv.ConvertDirectory(absSrcDir, absDestDir); err != nil {
 		log.Fatalf("Конвертация не удалась: %v", err)
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/config"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vaultdb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func runSQLite(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("sqlite", flag.ExitOnError)
+	outPath := fs.String("out", "vault.db", "Файл базы SQLite")
+	full := fs.Bool("full", false, "Перезаписать все заметки, даже неизменённые")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	absSrcDir, err := filepath.Abs(cfg.SrcDir)
+	if err != nil {
+		return err
+	}
+
+	vaultOpts, err := vaultOptions(cfg)
+	if err != nil {
+		return err
+	}
+	v, err := vault.Load(absSrcDir, vaultOpts...)
+	if err != nil {
+		return err
+	}
+
+	conv, err := newConverter(cfg)
+	if err != nil {
+		return err
+	}
+	render := func(note *vault.Note) ([]byte, error) {
+		return conv.RenderNote(note, absSrcDir)
+	}
+
+	stats, err := vaultdb.Sync(*outPath, v, render, *full)
+	if err != nil {
+		return err
+	}
+	log.Infof("База %s обновлена: добавлено %d, обновлено %d, без изменений %d, удалено %d",
+		*outPath, stats.Added, stats.Updated, stats.Unchanged, stats.Removed)
+	return nil
+}
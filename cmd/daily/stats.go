@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/config"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/analytics"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func runStats(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	format := fs.String("format", analytics.FormatText, "Формат отчёта: text, json или csv")
+	outPath := fs.String("out", "", "Файл для отчёта (по умолчанию stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	absSrcDir, err := filepath.Abs(cfg.SrcDir)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Сбор статистики по заметкам из %s", absSrcDir)
+
+	vaultOpts, err := vaultOptions(cfg)
+	if err != nil {
+		return err
+	}
+	loc, err := location(cfg)
+	if err != nil {
+		return err
+	}
+	v, err := vault.Load(absSrcDir, vaultOpts...)
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*outPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	return analytics.Write(out, analytics.BuildReport(v, time.Now().In(loc)), *format)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/config"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/schema"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// runValidate проверяет FrontMatter заметок по правилам schema из конфигурации.
+// Ненулевой код выхода — только при нарушениях уровня error
+func runValidate(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	format := fs.String("format", schema.FormatText, "Формат отчёта: text или json")
+	outPath := fs.String("out", "", "Файл для отчёта (по умолчанию stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(cfg.Schema) == 0 {
+		log.Warn("В конфигурации нет правил schema, проверять нечего")
+	}
+	rules := make([]schema.Rule, 0, len(cfg.Schema))
+	for _, r := range cfg.Schema {
+		rules = append(rules, schema.Rule{
+			Field:         r.Field,
+			Required:      r.Required,
+			Type:          r.Type,
+			Pattern:       r.Pattern,
+			MatchFilename: r.MatchFilename,
+			Severity:      r.Severity,
+			Path:          r.Path,
+		})
+	}
+	validator, err := schema.New(rules)
+	if err != nil {
+		return err
+	}
+
+	absSrcDir, err := filepath.Abs(cfg.SrcDir)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Проверка FrontMatter заметок из %s", absSrcDir)
+
+	violations, err := validator.ValidateDir(absSrcDir)
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*outPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := schema.Write(out, violations, *format); err != nil {
+		return err
+	}
+	if schema.HasErrors(violations) {
+		return fmt.Errorf("FrontMatter не соответствует схеме")
+	}
+	return nil
+}
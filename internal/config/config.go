@@ -10,6 +10,30 @@ type Config struct {
 	SrcDir   string `yaml:"src_dir"`
 	DestDir  string `yaml:"dest_dir"`
 	LogLevel string `yaml:"log_level"`
+	// Автор новых ежедневных заметок
+	Author string `yaml:"author"`
+	// Путь к шаблону новой ежедневной заметки (text/template); пусто — встроенный шаблон
+	DailyTemplate string `yaml:"daily_template"`
+	// Рендерер Markdown: goldmark (по умолчанию) или blackfriday
+	Renderer  string          `yaml:"renderer"`
+	Highlight HighlightConfig `yaml:"highlight"`
+	Mermaid   MermaidConfig   `yaml:"mermaid"`
+	TOC       TOCConfig       `yaml:"toc"`
+	URLs      URLsConfig      `yaml:"urls"`
+	Dates     DatesConfig     `yaml:"dates"`
+	Fix       FixConfig       `yaml:"fix"`
+	Site      SiteConfig      `yaml:"site"`
+	Feeds     FeedsConfig     `yaml:"feeds"`
+	Sitemap   SitemapConfig   `yaml:"sitemap"`
+	Robots    RobotsConfig    `yaml:"robots"`
+	// Правила проверки FrontMatter командой validate
+	Schema []SchemaRule `yaml:"schema"`
+}
+
+type HighlightConfig struct {
+	// Тема chroma, например github, monokai, dracula; пусто — github
+	Theme       string `yaml:"theme"`
+	LineNumbers bool   `yaml:"line_numbers"`
 }
 
 func LoadConfig(configPath string) (*Config, error) {
@@ -26,3 +50,85 @@ func LoadConfig(configPath string) (*Config, error) {
 
 	return &cfg, nil
 }
+
+type MermaidConfig struct {
+	// script — <div class="mermaid"> и локальный скрипт, svg — отрисовка через mermaid-cli
+	Mode string `yaml:"mode"`
+	// Путь к своей копии mermaid.min.js, которая копируется в dest_dir; пусто — встроенная копия
+	Script string `yaml:"script"`
+	// Команда mermaid-cli; пусто — mmdc
+	CLI string `yaml:"cli"`
+}
+
+type TOCConfig struct {
+	// Оглавление выводится на страницах заметок с таким числом заголовков и больше; 0 — только по маркеру [TOC]
+	MinHeadings int `yaml:"min_headings"`
+}
+
+type URLsConfig struct {
+	// name — имя исходного файла, translit — латинский слаг, date — 2024/12/09/index.html
+	Style string `yaml:"style"`
+}
+
+type DatesConfig struct {
+	// Часовой пояс дат без смещения, например Europe/Moscow; пусто — UTC
+	Timezone string `yaml:"timezone"`
+	// Откуда брать дату заметки без корректного поля date: filename, mtime
+	Fallback []string `yaml:"fallback"`
+}
+
+type FixConfig struct {
+	// Порядок ключей FrontMatter для команды fix; остальные ключи идут следом
+	KeyOrder []string `yaml:"key_order"`
+}
+
+type SiteConfig struct {
+	// Адрес, по которому опубликована dest_dir, например https://notes.example.com/daily/
+	BaseURL     string `yaml:"base_url"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+}
+
+type FeedsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Число записей в ленте; 0 — 20
+	Limit int `yaml:"limit"`
+	// full — полный HTML заметки, summary — краткое содержание
+	Content string `yaml:"content"`
+	// Отдельные ленты для каждого тега
+	Tags bool `yaml:"tags"`
+}
+
+type SitemapConfig struct {
+	// sitemap.xml с опубликованными заметками; нужен site.base_url
+	Enabled bool `yaml:"enabled"`
+}
+
+type RobotsConfig struct {
+	// robots.txt в dest_dir; поисковики читают его только из корня хоста
+	Enabled bool         `yaml:"enabled"`
+	Rules   []RobotsRule `yaml:"rules"`
+}
+
+type RobotsRule struct {
+	// Пусто — *
+	UserAgent string `yaml:"user_agent"`
+	// Пути от корня сайта; путь из site.base_url добавляется автоматически
+	Allow    []string `yaml:"allow"`
+	Disallow []string `yaml:"disallow"`
+}
+
+type SchemaRule struct {
+	Field    string `yaml:"field"`
+	Required bool   `yaml:"required"`
+	// string, date, bool, number или list; пусто — любой тип
+	Type string `yaml:"type"`
+	// Регулярное выражение для строки или каждого элемента списка
+	Pattern string `yaml:"pattern"`
+	// Дата должна совпадать с датой ГГГГ-ММ-ДД в имени файла
+	MatchFilename bool `yaml:"match_filename"`
+	// error (по умолчанию) или warning
+	Severity string `yaml:"severity"`
+	// Регулярное выражение для пути заметки относительно src_dir; пусто — все заметки
+	Path string `yaml:"path"`
+}
@@ -0,0 +1,181 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+func Write(w io.Writer, r *Report, format string) error {
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return WriteText(w, r)
+	case FormatJSON:
+		return WriteJSON(w, r)
+	case FormatCSV:
+		return WriteCSV(w, r)
+	default:
+		return fmt.Errorf("неизвестный формат отчёта: %s", format)
+	}
+}
+
+func WriteJSON(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("не удалось записать JSON отчёт: %v", err)
+	}
+	return nil
+}
+
+// WriteCSV пишет отчёт в «длинном» формате: section,period,key,value
+func WriteCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+
+	rows := [][]string{
+		{"section", "period", "key", "value"},
+		{"summary", "", "total_notes", strconv.Itoa(r.TotalNotes)},
+		{"summary", "", "dated_notes", strconv.Itoa(r.DatedNotes)},
+		{"summary", "", "closed_notes", strconv.Itoa(r.ClosedNotes)},
+		{"summary", "", "open_notes", strconv.Itoa(r.OpenNotes)},
+		{"summary", "", "closed_ratio", formatFloat(r.ClosedRatio)},
+		{"summary", "", "total_words", strconv.Itoa(r.TotalWords)},
+		{"summary", "", "average_words", formatFloat(r.AverageWords)},
+		{"summary", "", "longest_streak", strconv.Itoa(r.LongestStreak)},
+		{"summary", "", "current_streak", strconv.Itoa(r.CurrentStreak)},
+	}
+
+	periods := []struct {
+		section string
+		counts  []PeriodCount
+	}{
+		{"day", r.PerDay},
+		{"week", r.PerWeek},
+		{"month", r.PerMonth},
+	}
+	for _, p := range periods {
+		for _, c := range p.counts {
+			rows = append(rows,
+				[]string{p.section, c.Period, "notes", strconv.Itoa(c.Notes)},
+				[]string{p.section, c.Period, "words", strconv.Itoa(c.Words)},
+			)
+		}
+	}
+
+	for _, s := range r.Streaks {
+		rows = append(rows, []string{"streak", s.Start + "/" + s.End, "days", strconv.Itoa(s.Days)})
+	}
+	for _, g := range r.Gaps {
+		rows = append(rows, []string{"gap", g.Start + "/" + g.End, "days", strconv.Itoa(g.Days)})
+	}
+	for _, t := range r.Tags {
+		for _, m := range t.Months {
+			rows = append(rows, []string{"tag", m.Period, t.Tag, strconv.Itoa(m.Notes)})
+		}
+	}
+	for _, sc := range r.StatusByMonth {
+		rows = append(rows,
+			[]string{"status", sc.Period, "closed", strconv.Itoa(sc.Closed)},
+			[]string{"status", sc.Period, "open", strconv.Itoa(sc.Open)},
+		)
+	}
+	for _, l := range r.TopLinked {
+		rows = append(rows, []string{"linked", "", l.Path, strconv.Itoa(l.Incoming)})
+	}
+	for _, f := range r.Fields {
+		rows = append(rows,
+			[]string{"field", f.Field, "notes", strconv.Itoa(f.Notes)},
+			[]string{"field", f.Field, "min", formatFloat(f.Min)},
+			[]string{"field", f.Field, "max", formatFloat(f.Max)},
+			[]string{"field", f.Field, "average", formatFloat(f.Average)},
+		)
+	}
+
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("не удалось записать CSV отчёт: %v", err)
+	}
+	return nil
+}
+
+func WriteText(w io.Writer, r *Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Всего заметок: %d (с датой: %d, без даты: %d)\n", r.TotalNotes, r.DatedNotes, len(r.UndatedNotes))
+	if r.FirstDate != "" {
+		fmt.Fprintf(&b, "Период: %s — %s\n", r.FirstDate, r.LastDate)
+	}
+	fmt.Fprintf(&b, "Закрытые / открытые: %d / %d (%.1f%% закрыто)\n", r.ClosedNotes, r.OpenNotes, r.ClosedRatio*100)
+	fmt.Fprintf(&b, "Слов всего: %d, в среднем на заметку: %.1f\n", r.TotalWords, r.AverageWords)
+	fmt.Fprintf(&b, "Самая длинная серия: %d дн., текущая серия: %d дн.\n", r.LongestStreak, r.CurrentStreak)
+
+	writeCounts(&b, "Заметки по месяцам", r.PerMonth)
+	writeCounts(&b, "Заметки по неделям", r.PerWeek)
+	writeCounts(&b, "Заметки по дням", r.PerDay)
+
+	if len(r.Gaps) > 0 {
+		b.WriteString("\nПропуски:\n")
+		for _, g := range r.Gaps {
+			fmt.Fprintf(&b, "  %s — %s: %d дн.\n", g.Start, g.End, g.Days)
+		}
+	}
+
+	if len(r.Tags) > 0 {
+		b.WriteString("\nТеги:\n")
+		for _, t := range r.Tags {
+			months := make([]string, 0, len(t.Months))
+			for _, m := range t.Months {
+				months = append(months, fmt.Sprintf("%s: %d", m.Period, m.Notes))
+			}
+			fmt.Fprintf(&b, "  %s — %d (%s)\n", t.Tag, t.Total, strings.Join(months, ", "))
+		}
+	}
+
+	if len(r.Fields) > 0 {
+		b.WriteString("\nЧисловые поля:\n")
+		for _, f := range r.Fields {
+			fmt.Fprintf(&b, "  %s — заметок %d, мин %.2f, макс %.2f, среднее %.2f\n", f.Field, f.Notes, f.Min, f.Max, f.Average)
+		}
+	}
+
+	if len(r.TopLinked) > 0 {
+		b.WriteString("\nСамые цитируемые заметки:\n")
+		for _, l := range r.TopLinked {
+			fmt.Fprintf(&b, "  %s — %d\n", l.Path, l.Incoming)
+		}
+	}
+
+	if len(r.UndatedNotes) > 0 {
+		b.WriteString("\nЗаметки без даты:\n")
+		for _, n := range r.UndatedNotes {
+			fmt.Fprintf(&b, "  %s\n", n)
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("не удалось записать отчёт: %v", err)
+	}
+	return nil
+}
+
+func writeCounts(b *strings.Builder, title string, counts []PeriodCount) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n", title)
+	for _, c := range counts {
+		fmt.Fprintf(b, "  %s: заметок %d, слов %d\n", c.Period, c.Notes, c.Words)
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
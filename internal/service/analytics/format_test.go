@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite_Formats(t *testing.T) {
+	r := BuildReport(vault.New("", []*vault.Note{
+		newNote("a.md", "2024-12-09", true, 5, "#daily"),
+	}), time.Date(2024, 12, 9, 12, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		name   string
+		format string
+		check  func(t *testing.T, out []byte)
+	}{
+		{
+			name:   "text",
+			format: FormatText,
+			check: func(t *testing.T, out []byte) {
+				require.Contains(t, string(out), "Всего заметок: 1")
+				require.Contains(t, string(out), "#daily — 1")
+			},
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			check: func(t *testing.T, out []byte) {
+				var decoded Report
+				require.NoError(t, json.Unmarshal(out, &decoded))
+				require.Equal(t, 1, decoded.TotalNotes)
+				require.Equal(t, "2024-12-09", decoded.FirstDate)
+			},
+		},
+		{
+			name:   "csv",
+			format: FormatCSV,
+			check: func(t *testing.T, out []byte) {
+				rows, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+				require.NoError(t, err)
+				require.Equal(t, []string{"section", "period", "key", "value"}, rows[0])
+				require.Contains(t, rows, []string{"day", "2024-12-09", "notes", "1"})
+				require.Contains(t, rows, []string{"tag", "2024-12", "#daily", "1"})
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Write(&buf, r, tc.format)
+
+			require.NoError(t, err)
+			tc.check(t, buf.Bytes())
+		})
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Write(&buf, BuildReport(vault.New("", nil), time.Now()), "xml")
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "неизвестный формат отчёта")
+}
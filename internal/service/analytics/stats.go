@@ -0,0 +1,304 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+type PeriodCount struct {
+	Period string `json:"period"`
+	Notes  int    `json:"notes"`
+	Words  int    `json:"words"`
+}
+
+type Span struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Days  int    `json:"days"`
+}
+
+type TagTrend struct {
+	Tag    string        `json:"tag"`
+	Total  int           `json:"total"`
+	Months []PeriodCount `json:"months"`
+}
+
+type StatusCount struct {
+	Period string `json:"period"`
+	Closed int    `json:"closed"`
+	Open   int    `json:"open"`
+}
+
+type LinkCount struct {
+	Note     string `json:"note"`
+	Path     string `json:"path"`
+	Incoming int    `json:"incoming"`
+}
+
+// FieldSummary — сводка по числовому полю (mood, sleep и т.п.); длительности считаются в часах
+type FieldSummary struct {
+	Field   string  `json:"field"`
+	Notes   int     `json:"notes"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Average float64 `json:"average"`
+}
+
+// Сколько самых популярных заметок попадает в отчёт
+const topLinkedLimit = 10
+
+type Report struct {
+	TotalNotes    int            `json:"total_notes"`
+	DatedNotes    int            `json:"dated_notes"`
+	UndatedNotes  []string       `json:"undated_notes,omitempty"`
+	FirstDate     string         `json:"first_date,omitempty"`
+	LastDate      string         `json:"last_date,omitempty"`
+	PerDay        []PeriodCount  `json:"per_day"`
+	PerWeek       []PeriodCount  `json:"per_week"`
+	PerMonth      []PeriodCount  `json:"per_month"`
+	Streaks       []Span         `json:"streaks"`
+	Gaps          []Span         `json:"gaps"`
+	LongestStreak int            `json:"longest_streak"`
+	CurrentStreak int            `json:"current_streak"`
+	Tags          []TagTrend     `json:"tags"`
+	ClosedNotes   int            `json:"closed_notes"`
+	OpenNotes     int            `json:"open_notes"`
+	ClosedRatio   float64        `json:"closed_ratio"`
+	StatusByMonth []StatusCount  `json:"status_by_month"`
+	TopLinked     []LinkCount    `json:"top_linked"`
+	Fields        []FieldSummary `json:"fields"`
+	TotalWords    int            `json:"total_words"`
+	AverageWords  float64        `json:"average_words"`
+}
+
+func BuildReport(v *vault.Vault, now time.Time) *Report {
+	notes := v.Notes
+	r := &Report{
+		TotalNotes: len(notes),
+		PerDay:     []PeriodCount{},
+		PerWeek:    []PeriodCount{},
+		PerMonth:   []PeriodCount{},
+		Streaks:    []Span{},
+		Gaps:       []Span{},
+		Tags:       []TagTrend{},
+		TopLinked:  []LinkCount{},
+	}
+
+	days := newCounter()
+	weeks := newCounter()
+	months := newCounter()
+	tagMonths := make(map[string]*counter)
+	tagTotals := make(map[string]int)
+	status := make(map[string]*StatusCount)
+
+	for _, note := range notes {
+		r.TotalWords += note.WordCount
+		closed := note.FrontMatter != nil && note.FrontMatter.Closed
+		if closed {
+			r.ClosedNotes++
+		} else {
+			r.OpenNotes++
+		}
+
+		date, ok := note.Date()
+		if !ok {
+			r.UndatedNotes = append(r.UndatedNotes, note.RelPath)
+			continue
+		}
+		r.DatedNotes++
+
+		month := MonthKey(date)
+		days.add(date.Format(vault.DateLayout), note.WordCount)
+		weeks.add(WeekKey(date), note.WordCount)
+		months.add(month, note.WordCount)
+
+		if status[month] == nil {
+			status[month] = &StatusCount{Period: month}
+		}
+		if closed {
+			status[month].Closed++
+		} else {
+			status[month].Open++
+		}
+
+		for _, tag := range note.Tags {
+			if tagMonths[tag] == nil {
+				tagMonths[tag] = newCounter()
+			}
+			tagMonths[tag].add(month, note.WordCount)
+			tagTotals[tag]++
+		}
+	}
+
+	if r.TotalNotes > 0 {
+		r.ClosedRatio = float64(r.ClosedNotes) / float64(r.TotalNotes)
+		r.AverageWords = float64(r.TotalWords) / float64(r.TotalNotes)
+	}
+
+	r.PerDay = days.sorted()
+	r.PerWeek = weeks.sorted()
+	r.PerMonth = months.sorted()
+
+	if len(r.PerDay) > 0 {
+		r.FirstDate = r.PerDay[0].Period
+		r.LastDate = r.PerDay[len(r.PerDay)-1].Period
+		r.Streaks, r.Gaps = streaksAndGaps(r.PerDay)
+		for _, s := range r.Streaks {
+			if s.Days > r.LongestStreak {
+				r.LongestStreak = s.Days
+			}
+		}
+		// Текущая серия должна доходить до сегодняшнего или вчерашнего дня, иначе она уже прервалась
+		last := r.Streaks[len(r.Streaks)-1]
+		if last.End == now.Format(vault.DateLayout) || last.End == now.AddDate(0, 0, -1).Format(vault.DateLayout) {
+			r.CurrentStreak = last.Days
+		}
+	}
+
+	r.StatusByMonth = make([]StatusCount, 0, len(status))
+	for _, sc := range status {
+		r.StatusByMonth = append(r.StatusByMonth, *sc)
+	}
+	sort.Slice(r.StatusByMonth, func(i, j int) bool {
+		return r.StatusByMonth[i].Period < r.StatusByMonth[j].Period
+	})
+
+	r.TopLinked = topLinked(v, topLinkedLimit)
+	r.Fields = fieldSummaries(notes)
+
+	for tag, c := range tagMonths {
+		r.Tags = append(r.Tags, TagTrend{Tag: tag, Total: tagTotals[tag], Months: c.sorted()})
+	}
+	sort.Slice(r.Tags, func(i, j int) bool {
+		if r.Tags[i].Total != r.Tags[j].Total {
+			return r.Tags[i].Total > r.Tags[j].Total
+		}
+		return r.Tags[i].Tag < r.Tags[j].Tag
+	})
+
+	return r
+}
+
+func topLinked(v *vault.Vault, limit int) []LinkCount {
+	result := []LinkCount{}
+	for note, incoming := range v.Backlinks() {
+		result = append(result, LinkCount{Note: note.Name, Path: note.RelPath, Incoming: incoming})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Incoming != result[j].Incoming {
+			return result[i].Incoming > result[j].Incoming
+		}
+		return result[i].Path < result[j].Path
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func fieldSummaries(notes []*vault.Note) []FieldSummary {
+	byField := make(map[string]*FieldSummary)
+	sums := make(map[string]float64)
+
+	for _, note := range notes {
+		for key, value := range note.Metadata {
+			// Повторяющееся поле учитывается по первому значению
+			if list, ok := value.([]any); ok && len(list) > 0 {
+				value = list[0]
+			}
+			n, ok := vault.NumericValue(value)
+			if !ok {
+				continue
+			}
+			fs := byField[key]
+			if fs == nil {
+				fs = &FieldSummary{Field: key, Min: n, Max: n}
+				byField[key] = fs
+			}
+			fs.Notes++
+			fs.Min = min(fs.Min, n)
+			fs.Max = max(fs.Max, n)
+			sums[key] += n
+		}
+	}
+
+	result := make([]FieldSummary, 0, len(byField))
+	for key, fs := range byField {
+		fs.Average = sums[key] / float64(fs.Notes)
+		result = append(result, *fs)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Field < result[j].Field
+	})
+	return result
+}
+
+func MonthKey(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+func WeekKey(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// streaksAndGaps разбивает отсортированные дни на серии подряд идущих дней и пропуски между ними
+func streaksAndGaps(days []PeriodCount) ([]Span, []Span) {
+	streaks := []Span{}
+	gaps := []Span{}
+
+	var start, prev time.Time
+	for i, d := range days {
+		cur, _ := time.Parse(vault.DateLayout, d.Period)
+		if i == 0 {
+			start, prev = cur, cur
+			continue
+		}
+		diff := int(cur.Sub(prev).Hours() / 24)
+		if diff > 1 {
+			streaks = append(streaks, newSpan(start, prev))
+			gaps = append(gaps, newSpan(prev.AddDate(0, 0, 1), cur.AddDate(0, 0, -1)))
+			start = cur
+		}
+		prev = cur
+	}
+	streaks = append(streaks, newSpan(start, prev))
+
+	return streaks, gaps
+}
+
+func newSpan(start, end time.Time) Span {
+	return Span{
+		Start: start.Format(vault.DateLayout),
+		End:   end.Format(vault.DateLayout),
+		Days:  int(end.Sub(start).Hours()/24) + 1,
+	}
+}
+
+type counter struct {
+	notes map[string]int
+	words map[string]int
+}
+
+func newCounter() *counter {
+	return &counter{notes: make(map[string]int), words: make(map[string]int)}
+}
+
+func (c *counter) add(key string, words int) {
+	c.notes[key]++
+	c.words[key] += words
+}
+
+func (c *counter) sorted() []PeriodCount {
+	result := make([]PeriodCount, 0, len(c.notes))
+	for key, n := range c.notes {
+		result = append(result, PeriodCount{Period: key, Notes: n, Words: c.words[key]})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Period < result[j].Period
+	})
+	return result
+}
@@ -0,0 +1,129 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func newNote(relPath, date string, closed bool, words int, tags ...string) *vault.Note {
+	return &vault.Note{
+		RelPath:     relPath,
+		Name:        strings.TrimSuffix(relPath, ".md"),
+		FrontMatter: &vault.FrontMatter{Date: date, Closed: closed},
+		Tags:        tags,
+		WordCount:   words,
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	notes := []*vault.Note{
+		newNote("2024-12-01.md", "2024-12-01", true, 10, "#daily"),
+		newNote("2024-12-02.md", "2024-12-02", true, 20, "#daily", "#go"),
+		newNote("2024-12-03.md", "2024-12-03", false, 30, "#daily"),
+		newNote("2024-12-06.md", "2024-12-06", false, 40, "#go"),
+		newNote("2025-01-01.md", "2025-01-01", false, 0),
+		newNote("idea.md", "", false, 0),
+	}
+
+	notes[0].Links = []vault.Link{{Target: "2024-12-02", Wiki: true}}
+	notes[2].Links = []vault.Link{{Target: "2024-12-02", Wiki: true}, {Target: "idea", Wiki: true}}
+	notes[5].Links = []vault.Link{{Target: "idea", Wiki: true}, {Target: "https://example.com"}}
+
+	notes[0].Metadata = map[string]any{"mood": 6.0, "sleep": 7 * time.Hour, "weather": "rain"}
+	notes[1].Metadata = map[string]any{"mood": []any{8.0, 2.0}, "sleep": 8 * time.Hour}
+
+	r := BuildReport(vault.New("", notes), time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC))
+
+	require.Equal(t, 6, r.TotalNotes)
+	require.Equal(t, 5, r.DatedNotes)
+	require.Equal(t, []string{"idea.md"}, r.UndatedNotes)
+	require.Equal(t, "2024-12-01", r.FirstDate)
+	require.Equal(t, "2025-01-01", r.LastDate)
+
+	require.Equal(t, 2, r.ClosedNotes)
+	require.Equal(t, 4, r.OpenNotes)
+	require.InDelta(t, 2.0/6.0, r.ClosedRatio, 0.0001)
+	require.Equal(t, 100, r.TotalWords)
+	require.InDelta(t, 100.0/6.0, r.AverageWords, 0.0001)
+
+	require.Equal(t, []PeriodCount{
+		{Period: "2024-12", Notes: 4, Words: 100},
+		{Period: "2025-01", Notes: 1, Words: 0},
+	}, r.PerMonth)
+	require.Len(t, r.PerDay, 5)
+	require.Equal(t, "2024-W48", r.PerWeek[0].Period)
+
+	require.Equal(t, []Span{
+		{Start: "2024-12-01", End: "2024-12-03", Days: 3},
+		{Start: "2024-12-06", End: "2024-12-06", Days: 1},
+		{Start: "2025-01-01", End: "2025-01-01", Days: 1},
+	}, r.Streaks)
+	require.Equal(t, []Span{
+		{Start: "2024-12-04", End: "2024-12-05", Days: 2},
+		{Start: "2024-12-07", End: "2024-12-31", Days: 25},
+	}, r.Gaps)
+	require.Equal(t, 3, r.LongestStreak)
+	require.Equal(t, 1, r.CurrentStreak)
+
+	require.Equal(t, []StatusCount{
+		{Period: "2024-12", Closed: 2, Open: 2},
+		{Period: "2025-01", Closed: 0, Open: 1},
+	}, r.StatusByMonth)
+	require.Equal(t, []LinkCount{
+		{Note: "2024-12-02", Path: "2024-12-02.md", Incoming: 2},
+		{Note: "idea", Path: "idea.md", Incoming: 1},
+	}, r.TopLinked)
+
+	require.Equal(t, []FieldSummary{
+		{Field: "mood", Notes: 2, Min: 6, Max: 8, Average: 7},
+		{Field: "sleep", Notes: 2, Min: 7, Max: 8, Average: 7.5},
+	}, r.Fields)
+
+	require.Equal(t, "#daily", r.Tags[0].Tag)
+	require.Equal(t, 3, r.Tags[0].Total)
+	require.Equal(t, "#go", r.Tags[1].Tag)
+	require.Equal(t, 2, r.Tags[1].Total)
+}
+
+func TestBuildReport_CurrentStreak(t *testing.T) {
+	notes := []*vault.Note{
+		newNote("2024-12-01.md", "2024-12-01", false, 0),
+		newNote("2024-12-02.md", "2024-12-02", false, 0),
+	}
+
+	testCases := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{name: "серия заканчивается сегодня", now: time.Date(2024, 12, 2, 23, 0, 0, 0, time.UTC), want: 2},
+		{name: "серия заканчивается вчера", now: time.Date(2024, 12, 3, 8, 0, 0, 0, time.UTC), want: 2},
+		{name: "серия давно прервалась", now: time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC), want: 0},
+		{
+			name: "сегодня по часовому поясу заметок",
+			now:  time.Date(2024, 12, 3, 22, 30, 0, 0, time.UTC).In(time.FixedZone("UTC+3", 3*60*60)),
+			want: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := BuildReport(vault.New("", notes), tc.now)
+
+			require.Equal(t, 2, r.LongestStreak)
+			require.Equal(t, tc.want, r.CurrentStreak)
+		})
+	}
+}
+
+func TestBuildReport_Empty(t *testing.T) {
+	r := BuildReport(vault.New("", nil), time.Now())
+
+	require.Equal(t, 0, r.TotalNotes)
+	require.Empty(t, r.Streaks)
+	require.Zero(t, r.AverageWords)
+}
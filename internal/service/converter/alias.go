@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/page"
+	log "github.com/sirupsen/logrus"
+)
+
+// aliasPlugin пишет по странице-перенаправлению на каждый псевдоним заметки из aliases,
+// чтобы ссылки на старое имя после переименования заметки продолжали работать
+func aliasPlugin(style string) Plugin {
+	return Plugin{
+		Name: "aliases",
+		AfterRun: func(run *Run) ([]Page, error) {
+			v, err := run.Vault()
+			if err != nil {
+				log.Errorf("Не удалось загрузить заметки: %v", err)
+				return nil, fmt.Errorf("не удалось загрузить заметки: %v", err)
+			}
+
+			// Страница псевдонима не должна затереть страницу заметки или другого псевдонима
+			taken := make(map[string]string, len(v.Notes))
+			for _, note := range v.Notes {
+				taken[strings.ToLower(run.PageURL(note.RelPath))] = note.RelPath
+			}
+
+			var pages []Page
+			for _, note := range v.Notes {
+				if note.FrontMatter == nil {
+					continue
+				}
+				target := run.PageURL(note.RelPath)
+				for _, alias := range note.FrontMatter.Aliases {
+					alias = strings.TrimSpace(alias)
+					if alias == "" {
+						continue
+					}
+					file := aliasPath(alias, style)
+					if owner, ok := taken[strings.ToLower(file)]; ok {
+						if owner != note.RelPath {
+							log.Warnf("Псевдоним %s заметки %s совпадает с %s, перенаправление не создано", alias, note.RelPath, owner)
+						}
+						continue
+					}
+					taken[strings.ToLower(file)] = note.RelPath
+
+					var buf bytes.Buffer
+					if err := page.Redirect(&buf, note.Title(), escapePath(relURL(file, target))); err != nil {
+						return nil, err
+					}
+					pages = append(pages, Page{Path: file, Content: buf.Bytes()})
+				}
+			}
+			return pages, nil
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasPlugin(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(srcDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644))
+	}
+	write("projects/Новое имя.md", "---\naliases: [Старое имя, idea]\n---\n# Проект\n")
+	write("idea.md", "См. [[старое имя#Итоги|проект]] и [[idea]]\n")
+
+	require.NoError(t, NewConverter().ConvertDirectory(srcDir, destDir))
+
+	source, err := os.ReadFile(filepath.Join(destDir, "idea.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(source), `<a class="internal-link" href="%D0%9D%D0%BE%D0%B2%D0%BE%D0%B5%20%D0%B8%D0%BC%D1%8F.html#%D0%B8%D1%82%D0%BE%D0%B3%D0%B8">проект</a>`)
+	// Имя заметки важнее псевдонима
+	require.Contains(t, string(source), `<a class="internal-link" href="idea.html">idea</a>`)
+
+	redirect, err := os.ReadFile(filepath.Join(destDir, "Старое имя.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(redirect), `<meta http-equiv="refresh" content="0; url=%D0%9D%D0%BE%D0%B2%D0%BE%D0%B5%20%D0%B8%D0%BC%D1%8F.html">`)
+	require.Contains(t, string(redirect), "<title>Проект</title>")
+
+	// Псевдоним, совпавший с другой заметкой, не затирает её страницу
+	require.NotContains(t, string(source), "http-equiv")
+}
@@ -0,0 +1,112 @@
+package converter
+
+import (
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Идентификатор блока так же, как его находит разбор хранилища: «текст ^abc-123» или строка «^abc-123» после блока
+var blockIDRe = regexp.MustCompile(`(?:^|\s)\^([A-Za-z0-9-]+)\s*$`)
+
+// blockPlugin убирает идентификаторы блоков ^id из текста и выводит их атрибутами id,
+// чтобы ссылки [[Заметка#^id]] вели к нужному абзацу, элементу списка, таблице или цитате
+func blockPlugin() Plugin {
+	return Plugin{
+		Name:         "blocks",
+		PreParse:     checkBlockLinks,
+		TransformAST: renderBlockIDs,
+	}
+}
+
+// checkBlockLinks предупреждает о ссылках на блоки, которых нет в целевой заметке
+func checkBlockLinks(ctx *Context, md []byte) ([]byte, error) {
+	if ctx.Note == nil {
+		return md, nil
+	}
+	for _, link := range ctx.Note.Links {
+		if !link.Wiki || len(link.Anchor) < 2 || link.Anchor[0] != '^' {
+			continue
+		}
+		v, err := ctx.Vault()
+		if err != nil {
+			log.Debugf("Не удалось загрузить заметки для проверки ссылок на блоки: %v", err)
+			return md, nil
+		}
+		target := v.Resolve(ctx.Note, link)
+		if target != nil && !target.HasBlock(link.Anchor[1:]) {
+			log.Warnf("Блок %s не найден в заметке %s (ссылка из %s:%d)", link.Anchor, target.RelPath, ctx.Note.RelPath, link.Line)
+		}
+	}
+	return md, nil
+}
+
+func renderBlockIDs(ctx *Context, doc ast.Node, source []byte) {
+	var texts []*ast.Text
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Heading, *ast.CodeSpan, *ast.Link, *ast.AutoLink, *ast.Image:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			// Идентификатор стоит в конце строки
+			if n.SoftLineBreak() || n.HardLineBreak() || n.NextSibling() == nil {
+				texts = append(texts, n)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, t := range texts {
+		m := blockIDRe.FindSubmatchIndex(t.Segment.Value(source))
+		if m == nil {
+			continue
+		}
+		id := string(t.Segment.Value(source)[m[2]:m[3]])
+		block := t.Parent()
+		for block != nil && block.Type() != ast.TypeBlock {
+			block = block.Parent()
+		}
+		if block == nil {
+			continue
+		}
+
+		t.Segment = text.NewSegment(t.Segment.Start, t.Segment.Start+m[0])
+		if t.Segment.Len() == 0 {
+			block.RemoveChild(block, t)
+		}
+
+		switch {
+		case block.HasChildren():
+			// «текст ^id» — id у абзаца, а у пункта списка — у всего пункта
+			if _, ok := block.Parent().(*ast.ListItem); ok {
+				block = block.Parent()
+			}
+			block.SetAttributeString("id", []byte(id))
+		case block.PreviousSibling() == nil:
+			block.Parent().RemoveChild(block.Parent(), block)
+		default:
+			attachBlockID(block, id)
+		}
+	}
+}
+
+// attachBlockID переносит id с абзаца из одной строки «^id» на предыдущий блок: список, таблицу, цитату.
+// Абзацы перед такой строкой — обычно заглушки блоков кода, формул и выносок, которые заменяются целиком,
+// поэтому для них пустой абзац с id остаётся якорем перед блоком
+func attachBlockID(para ast.Node, id string) {
+	parent, prev := para.Parent(), para.PreviousSibling()
+	if _, ok := prev.(*ast.Paragraph); ok {
+		para.SetAttributeString("id", []byte(id))
+		para.SetAttributeString("class", []byte("block-anchor"))
+		parent.RemoveChild(parent, para)
+		parent.InsertBefore(parent, prev, para)
+		return
+	}
+	prev.SetAttributeString("id", []byte(id))
+	parent.RemoveChild(parent, para)
+}
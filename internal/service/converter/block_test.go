@@ -0,0 +1,106 @@
+package converter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockPlugin_IDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Абзац",
+			md:       "Важная мысль ^idea-1",
+			contains: []string{`<p id="idea-1">Важная мысль</p>`},
+		},
+		{
+			name:     "Абзац из нескольких строк",
+			md:       "Первая строка\nвторая **строка** ^two",
+			contains: []string{"<p id=\"two\">Первая строка\nвторая <strong>строка</strong></p>"},
+		},
+		{
+			name:     "Пункт списка",
+			md:       "- один ^first\n- два",
+			contains: []string{`<li id="first">один</li>`, "<li>два</li>"},
+		},
+		{
+			name:     "Строка после списка",
+			md:       "- один\n- два\n\n^list",
+			contains: []string{`<ul id="list">`},
+			excludes: []string{"^list", "<p></p>"},
+		},
+		{
+			name:     "Строка после цитаты",
+			md:       "> цитата\n\n^quote",
+			contains: []string{`<blockquote id="quote">`},
+		},
+		{
+			name:     "Строка после блока кода",
+			md:       "```go\nx := 1\n```\n\n^code",
+			contains: []string{"<p id=\"code\" class=\"block-anchor\"></p>\n<div class=\"highlight\" data-lang=\"go\">"},
+			excludes: []string{"^code"},
+		},
+		{
+			name:     "Выноска",
+			md:       "> [!note]\n> Совет ^tip",
+			contains: []string{`<p id="tip">Совет</p>`},
+		},
+		{
+			name:     "Не идентификатор",
+			md:       "x^2 и `код ^id` и 2 ^ 3",
+			contains: []string{"x^2 и <code>код ^id</code> и 2 ^ 3"},
+			excludes: []string{"id="},
+		},
+	}
+
+	sut := NewConverter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := sut.RenderNote(&vault.Note{Body: []byte(tt.md)}, "")
+			require.NoError(t, err)
+			for _, s := range tt.contains {
+				require.Contains(t, string(out), s)
+			}
+			for _, s := range tt.excludes {
+				require.NotContains(t, string(out), s)
+			}
+		})
+	}
+}
+
+func TestBlockPlugin_Links(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "target.md"), []byte("Мысль ^idea"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "source.md"), []byte("См. [[target#^idea]] и [[target#^missing|нет]]"), 0644))
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	require.NoError(t, NewConverter().ConvertDirectory(srcDir, destDir))
+
+	content, err := os.ReadFile(filepath.Join(destDir, "source.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), `<a class="internal-link" href="target.html#idea">target &gt; ^idea</a>`)
+	require.Contains(t, string(content), `<a class="internal-link" href="target.html#missing">нет</a>`)
+	require.Contains(t, logs.String(), "Блок ^missing не найден в заметке target.md (ссылка из source.md:1)")
+	require.NotContains(t, logs.String(), "Блок ^idea")
+}
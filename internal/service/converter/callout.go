@@ -0,0 +1,200 @@
+package converter
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Выноски Obsidian ("> [!note] Заголовок") вырезаются до рендеринга так же, как запросы Dataview:
+// иначе соседние цитаты склеиваются, а вложенность ломается
+const calloutPlaceholder = "CALLOUT-BLOCK-%d"
+
+var (
+	calloutPlaceholderRe = regexp.MustCompile(`(?:<p>)?CALLOUT-BLOCK-(\d+)(?:</p>)?`)
+	quoteLineRe          = regexp.MustCompile(`^ {0,3}> ?`)
+	calloutHeaderRe      = regexp.MustCompile(`^\s*\[!([\w-]+)\]([+-]?)\s*(.*)$`)
+)
+
+type calloutType struct {
+	class string
+	icon  string
+	title string
+}
+
+var calloutTypes = map[string]calloutType{
+	"note":      {"note", "✏️", "Заметка"},
+	"info":      {"info", "ℹ️", "Информация"},
+	"todo":      {"todo", "☑️", "Задача"},
+	"abstract":  {"abstract", "📋", "Кратко"},
+	"summary":   {"abstract", "📋", "Кратко"},
+	"tldr":      {"abstract", "📋", "Кратко"},
+	"tip":       {"tip", "💡", "Совет"},
+	"hint":      {"tip", "💡", "Совет"},
+	"important": {"tip", "💡", "Важно"},
+	"success":   {"success", "✅", "Готово"},
+	"check":     {"success", "✅", "Готово"},
+	"done":      {"success", "✅", "Готово"},
+	"question":  {"question", "❓", "Вопрос"},
+	"help":      {"question", "❓", "Вопрос"},
+	"faq":       {"question", "❓", "Вопрос"},
+	"warning":   {"warning", "⚠️", "Внимание"},
+	"caution":   {"warning", "⚠️", "Внимание"},
+	"attention": {"warning", "⚠️", "Внимание"},
+	"failure":   {"failure", "❌", "Неудача"},
+	"fail":      {"failure", "❌", "Неудача"},
+	"missing":   {"failure", "❌", "Неудача"},
+	"danger":    {"danger", "⛔", "Опасность"},
+	"error":     {"danger", "⛔", "Опасность"},
+	"bug":       {"bug", "🐞", "Ошибка"},
+	"example":   {"example", "📌", "Пример"},
+	"quote":     {"quote", "❝", "Цитата"},
+	"cite":      {"quote", "❝", "Цитата"},
+}
+
+type callout struct {
+	kind  string
+	title string
+	// '+' — сворачиваемая и раскрытая, '-' — свёрнутая, 0 — обычная
+	fold byte
+	body []byte
+}
+
+// extractCallouts вырезает выноски, возвращая текст с заглушками и сами выноски
+func extractCallouts(md []byte) ([]byte, []callout) {
+	lines := strings.Split(string(md), "\n")
+	var out []string
+	var callouts []callout
+
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if fence != "" || strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			switch {
+			case fence == "":
+				fence = trimmed[:3]
+			case strings.HasPrefix(trimmed, fence):
+				fence = ""
+			}
+			out = append(out, line)
+			continue
+		}
+
+		loc := quoteLineRe.FindStringIndex(line)
+		if loc == nil {
+			out = append(out, line)
+			continue
+		}
+		m := calloutHeaderRe.FindStringSubmatch(line[loc[1]:])
+		if m == nil {
+			out = append(out, line)
+			continue
+		}
+
+		c := callout{kind: strings.ToLower(m[1]), title: m[3]}
+		if m[2] != "" {
+			c.fold = m[2][0]
+		}
+		var body []string
+		for i+1 < len(lines) {
+			loc := quoteLineRe.FindStringIndex(lines[i+1])
+			if loc == nil {
+				break
+			}
+			body = append(body, lines[i+1][loc[1]:])
+			i++
+		}
+		c.body = []byte(strings.Join(body, "\n"))
+
+		out = append(out, "", fmt.Sprintf(calloutPlaceholder, len(callouts)), "")
+		callouts = append(callouts, c)
+	}
+
+	if len(callouts) == 0 {
+		return md, nil
+	}
+	return []byte(strings.Join(out, "\n")), callouts
+}
+
+func calloutPlugin() Plugin {
+	return Plugin{
+		Name: "callouts",
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			md, callouts := extractCallouts(md)
+			ctx.Set("callouts", callouts)
+			return md, nil
+		},
+		PostRender: func(ctx *Context, html []byte) ([]byte, error) {
+			callouts, _ := ctx.Get("callouts").([]callout)
+			return renderCallouts(html, callouts, ctx.Render)
+		},
+	}
+}
+
+// renderCallouts подставляет HTML выносок на место заглушек; содержимое выносок отрисовывает render
+func renderCallouts(htmlContent []byte, callouts []callout, render func([]byte) ([]byte, error)) ([]byte, error) {
+	if len(callouts) == 0 {
+		return htmlContent, nil
+	}
+	var renderErr error
+	out := calloutPlaceholderRe.ReplaceAllFunc(htmlContent, func(match []byte) []byte {
+		i, err := strconv.Atoi(string(calloutPlaceholderRe.FindSubmatch(match)[1]))
+		if err != nil || i >= len(callouts) {
+			return match
+		}
+		rendered, err := callouts[i].html(render)
+		if err != nil && renderErr == nil {
+			renderErr = err
+		}
+		return []byte(rendered)
+	})
+	return out, renderErr
+}
+
+func (c callout) html(render func([]byte) ([]byte, error)) (string, error) {
+	t, ok := calloutTypes[c.kind]
+	if !ok {
+		t = calloutType{class: c.kind, icon: calloutTypes["note"].icon, title: strings.ToUpper(c.kind[:1]) + c.kind[1:]}
+	}
+	title := t.title
+	if c.title != "" {
+		title = c.title
+	}
+
+	// Вложенные выноски обрабатываются рекурсивно
+	body, nested := extractCallouts(c.body)
+	content, err := render(body)
+	if err != nil {
+		return "", err
+	}
+	if content, err = renderCallouts(content, nested, render); err != nil {
+		return "", err
+	}
+
+	header := fmt.Sprintf(`<span class="callout-icon">%s</span><span class="callout-title-text">%s</span>`, t.icon, html.EscapeString(title))
+
+	var b strings.Builder
+	if c.fold == 0 {
+		fmt.Fprintf(&b, `<aside class="callout callout-%s">`+"\n", t.class)
+		fmt.Fprintf(&b, `<div class="callout-title">%s</div>`+"\n", header)
+	} else {
+		fmt.Fprintf(&b, `<aside class="callout callout-%s callout-foldable">`+"\n", t.class)
+		open := ""
+		if c.fold == '+' {
+			open = " open"
+		}
+		fmt.Fprintf(&b, "<details%s>\n", open)
+		fmt.Fprintf(&b, `<summary class="callout-title">%s</summary>`+"\n", header)
+	}
+	if len(strings.TrimSpace(string(c.body))) > 0 {
+		fmt.Fprintf(&b, "<div class=\"callout-content\">\n%s</div>\n", content)
+	}
+	if c.fold != 0 {
+		b.WriteString("</details>\n")
+	}
+	b.WriteString("</aside>\n")
+	return b.String(), nil
+}
@@ -0,0 +1,131 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func renderWithCallouts(md string) string {
+	r := NewGoldmarkRenderer()
+	out, callouts := extractCallouts([]byte(md))
+	htmlContent, err := r.Render(out)
+	if err != nil {
+		panic(err)
+	}
+	htmlContent, err = renderCallouts(htmlContent, callouts, r.Render)
+	if err != nil {
+		panic(err)
+	}
+	return string(htmlContent)
+}
+
+func TestCallouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       string
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "Выноска с заголовком",
+			md:   "> [!note] Мой <заголовок>\n> Текст **жирный**",
+			contains: []string{
+				`<aside class="callout callout-note">`,
+				`<span class="callout-icon">✏️</span><span class="callout-title-text">Мой &lt;заголовок&gt;</span>`,
+				"<div class=\"callout-content\">\n<p>Текст <strong>жирный</strong></p>",
+			},
+			excludes: []string{"<blockquote>", "<details"},
+		},
+		{
+			name: "Заголовок по умолчанию и синоним типа",
+			md:   "> [!CAUTION]\n> Осторожно",
+			contains: []string{
+				`<aside class="callout callout-warning">`,
+				`<span class="callout-title-text">Внимание</span>`,
+			},
+		},
+		{
+			name: "Свёрнутая выноска",
+			md:   "> [!warning]- Спойлер\n> Скрыто",
+			contains: []string{
+				`<aside class="callout callout-warning callout-foldable">`,
+				"<details>\n<summary class=\"callout-title\">",
+				"</details>\n</aside>",
+			},
+		},
+		{
+			name:     "Раскрытая сворачиваемая выноска",
+			md:       "> [!tip]+\n> Видно",
+			contains: []string{"<details open>"},
+		},
+		{
+			name: "Вложенная выноска",
+			md:   "> [!note] Внешняя\n> Текст\n> > [!bug] Внутренняя\n> > Ошибка",
+			contains: []string{
+				`<aside class="callout callout-note">`,
+				`<aside class="callout callout-bug">`,
+				"<p>Ошибка</p>",
+			},
+			excludes: []string{"[!bug]"},
+		},
+		{
+			name: "Соседние выноски и цитата не склеиваются",
+			md:   "> [!info]\n> Первая\n\n> [!note]\n> Вторая\n\n> Обычная цитата",
+			contains: []string{
+				`<aside class="callout callout-info">`,
+				`<aside class="callout callout-note">`,
+				"<blockquote>\n<p>Обычная цитата</p>\n</blockquote>",
+			},
+		},
+		{
+			name:     "Неизвестный тип",
+			md:       "> [!custom]\n> Текст",
+			contains: []string{`<aside class="callout callout-custom">`, `<span class="callout-title-text">Custom</span>`},
+		},
+		{
+			name:     "Внутри блока кода не трогается",
+			md:       "```\n> [!note]\n```",
+			contains: []string{"&gt; [!note]"},
+			excludes: []string{"<aside"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderWithCallouts(tt.md)
+			for _, s := range tt.contains {
+				require.Contains(t, got, s)
+			}
+			for _, s := range tt.excludes {
+				require.NotContains(t, got, s)
+			}
+		})
+	}
+}
+
+func TestConvertFile_WrapsPage(t *testing.T) {
+	sut := NewConverter()
+
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	path := filepath.Join(srcDir, "note.md")
+	require.NoError(t, os.WriteFile(path, []byte("# День\n\n> [!tip] Совет\n> Пить воду"), 0644))
+
+	require.NoError(t, sut.ConvertFile(path, srcDir, destDir))
+
+	content, err := os.ReadFile(filepath.Join(destDir, "note.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "<title>День</title>")
+	require.Contains(t, string(content), ".callout {")
+	require.Contains(t, string(content), `<aside class="callout callout-tip">`)
+}
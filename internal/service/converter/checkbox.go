@@ -0,0 +1,30 @@
+package converter
+
+import (
+	"regexp"
+)
+
+// Пункт списка, начинающийся с [ ], [x], [/], [-] или [>]; <p> появляется в «разреженных» списках
+var taskItemRe = regexp.MustCompile(`<li>(<p>)?\[([ xX/>-])\]\s?`)
+
+func checkboxPlugin() Plugin {
+	return Plugin{
+		Name: "checkboxes",
+		PostRender: func(ctx *Context, html []byte) ([]byte, error) {
+			return renderTaskCheckboxes(html), nil
+		},
+	}
+}
+
+// renderTaskCheckboxes заменяет текстовые [ ] и [x] в пунктах списков на чекбоксы
+func renderTaskCheckboxes(html []byte) []byte {
+	return taskItemRe.ReplaceAllFunc(html, func(match []byte) []byte {
+		m := taskItemRe.FindSubmatch(match)
+		attrs := " disabled"
+		switch string(m[2]) {
+		case "x", "X", "-", ">":
+			attrs += " checked"
+		}
+		return []byte(`<li class="task-list-item">` + string(m[1]) + `<input type="checkbox"` + attrs + `> `)
+	})
+}
@@ -0,0 +1,44 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/russross/blackfriday/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTaskCheckboxes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		markdown string
+		expected []string
+	}{
+		{
+			name:     "Компактный список",
+			markdown: "- [ ] открыта\n- [x] выполнена\n- обычный пункт\n",
+			expected: []string{
+				`<li class="task-list-item"><input type="checkbox" disabled> открыта</li>`,
+				`<li class="task-list-item"><input type="checkbox" disabled checked> выполнена</li>`,
+				`<li>обычный пункт</li>`,
+			},
+		},
+		{
+			name:     "Разреженный список",
+			markdown: "- [ ] первая\n\n- [X] вторая\n",
+			expected: []string{
+				`<li class="task-list-item"><p><input type="checkbox" disabled> первая</p></li>`,
+				`<li class="task-list-item"><p><input type="checkbox" disabled checked> вторая</p></li>`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			html := string(renderTaskCheckboxes(blackfriday.Run([]byte(tc.markdown))))
+
+			for _, expected := range tc.expected {
+				require.Contains(t, html, expected)
+			}
+		})
+	}
+}
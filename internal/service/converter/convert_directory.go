@@ -5,14 +5,109 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
 
 	log "github.com/sirupsen/logrus"
 )
 
-type Converter struct{}
+type Converter struct {
+	// Источник текущего времени; подменяется в тестах
+	now       func() time.Time
+	renderer  Renderer
+	plugins   []Plugin
+	highlight HighlightOptions
+	mermaid   MermaidOptions
+	toc       TOCOptions
+	urls      URLOptions
+	site      SiteOptions
+	feeds     FeedOptions
+	sitemap   SitemapOptions
+	// Настройки разбора заметок: часовой пояс и запасные источники даты
+	noteOpts []vault.Option
+
+	// Разобранное хранилище нужно запросам Dataview; загружается один раз за запуск
+	mu        sync.Mutex
+	vault     *vault.Vault
+	vaultRoot string
+	// Пути страниц заметок по относительным путям исходных файлов
+	paths map[string]string
+	// HTML заметок, отрисованных за текущий запуск: ленты не отрисовывают их повторно
+	rendered map[string][]byte
+}
+
+type Option func(*Converter)
+
+// currentTime возвращает текущее время в часовом поясе заметок
+func (c *Converter) currentTime() time.Time {
+	return c.now().In(vault.Location(c.noteOpts...))
+}
+
+// WithRenderer задаёт рендерер Markdown вместо goldmark по умолчанию
+func WithRenderer(r Renderer) Option {
+	return func(c *Converter) {
+		c.renderer = r
+	}
+}
+
+// WithVaultOptions задаёт настройки разбора заметок, например часовой пояс дат
+func WithVaultOptions(opts ...vault.Option) Option {
+	return func(c *Converter) {
+		c.noteOpts = opts
+	}
+}
+
+// builtinPlugins — возможности конвертера, подключённые через API плагинов.
+// Порядок важен: запросы Dataview, диаграммы, блоки кода и формулы вырезаются до остальной разметки,
+// вики-ссылки разворачиваются до полей в тексте, чтобы [поле:: [[Заметка]]] разбиралось целиком,
+// оглавление собирается по тексту заголовков до подсветки тегов,
+// а чекбоксы расставляются последними, уже внутри выносок
+func (c *Converter) builtinPlugins() []Plugin {
+	return []Plugin{
+		checkboxPlugin(),
+		dataviewPlugin(),
+		mermaidPlugin(c.mermaid),
+		highlightPlugin(c.highlight),
+		mathPlugin(),
+		wikilinkPlugin(),
+		mdLinkPlugin(),
+		inlineFieldsPlugin(),
+		calloutPlugin(),
+		blockPlugin(),
+		tocPlugin(c.toc),
+		tagPlugin(),
+		aliasPlugin(c.urls.Style),
+		dashboardPlugin(),
+		tasksPlugin(),
+		searchPlugin(),
+		feedPlugin(c.site, c.feeds),
+		sitemapPlugin(c.site, c.sitemap),
+	}
+}
 
-func NewConverter() *Converter {
-	return &Converter{}
+func NewConverter(opts ...Option) *Converter {
+	c := &Converter{
+		now:       time.Now,
+		renderer:  NewGoldmarkRenderer(),
+		highlight: HighlightOptions{Theme: DefaultHighlightTheme},
+		mermaid:   MermaidOptions{Mode: MermaidScript},
+		urls:      URLOptions{Style: URLName},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.plugins = append(c.plugins, c.builtinPlugins()...)
+
+	if _, ok := c.renderer.(ASTRenderer); !ok {
+		for _, p := range c.plugins {
+			if p.TransformAST != nil {
+				log.Warnf("Рендерер не поддерживает преобразования AST, плагин %s работает не полностью", p.Name)
+			}
+		}
+	}
+	return c
 }
 
 func (c *Converter) ConvertDirectory(srcDir, destDir string) error {
@@ -30,8 +125,16 @@ func (c *Converter) ConvertDirectory(srcDir, destDir string) error {
 
 	log.Infof("Начало конвертации директории: %s -> %s", srcDir, destDir)
 
+	// Заметки могли измениться с прошлого запуска
+	c.resetVault()
+
+	run := &Run{SrcDir: srcDir, DestDir: destDir, Now: c.currentTime(), conv: c}
+	if err := c.runHooks(run, false); err != nil {
+		return err
+	}
+
 	// Проход по всем файлам в исходной директории
-	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			log.Errorf("Ошибка при обходе файла %s: %v", path, err)
 			return err
@@ -50,4 +153,64 @@ func (c *Converter) ConvertDirectory(srcDir, destDir string) error {
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	return c.runHooks(run, true)
+}
+
+// loadVault возвращает разобранное хранилище srcDir, загружая его при первом обращении
+func (c *Converter) loadVault(srcDir string) (*vault.Vault, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.vault != nil && c.vaultRoot == srcDir {
+		return c.vault, nil
+	}
+
+	v, err := vault.Load(srcDir, c.noteOpts...)
+	if err != nil {
+		return nil, err
+	}
+	c.vault, c.vaultRoot = v, srcDir
+	c.paths = buildPaths(v, c.urls.Style)
+	return v, nil
+}
+
+// pageURL возвращает путь страницы заметки relPath относительно целевой директории.
+// Без хранилища путь строится по одному имени файла
+func (c *Converter) pageURL(srcDir, relPath string) string {
+	if srcDir != "" {
+		if _, err := c.loadVault(srcDir); err == nil {
+			c.mu.Lock()
+			p, ok := c.paths[relPath]
+			c.mu.Unlock()
+			if ok {
+				return p
+			}
+		}
+	}
+	name := strings.TrimSuffix(filepath.Base(relPath), filepath.Ext(relPath))
+	return notePath(&vault.Note{RelPath: relPath, Name: name}, c.urls.Style)
+}
+
+func (c *Converter) resetVault() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.vault = nil
+	c.paths = nil
+	c.rendered = nil
+}
+
+// remember сохраняет HTML заметки relPath, отрисованный при конвертации
+func (c *Converter) remember(relPath string, htmlContent []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rendered == nil {
+		c.rendered = make(map[string][]byte)
+	}
+	c.rendered[relPath] = htmlContent
 }
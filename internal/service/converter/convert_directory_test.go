@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestConverterDirectory_SourceDirDoesNotExist(t *testing.T) {
@@ -81,6 +82,43 @@ func TestConverterDirectory_WithMdFile(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestConverterDirectory_WritesVaultPages(t *testing.T) {
+	sut := NewConverter()
+	sut.now = func() time.Time {
+		return time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC)
+	}
+
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	mdFile := filepath.Join(srcDir, "2024-12-09.md")
+	err = os.WriteFile(mdFile, []byte("---\ndate: 2024-12-09\n---\n# Hello World\n- [ ] задача 📅 2024-12-01\n"), 0644)
+	require.NoError(t, err)
+
+	err = sut.ConvertDirectory(srcDir, destDir)
+	require.NoError(t, err)
+
+	dashboardPath := filepath.Join(destDir, "dashboard.html")
+	require.FileExists(t, dashboardPath)
+
+	content, err := os.ReadFile(dashboardPath)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "2024-12-09: заметок 1")
+
+	content, err = os.ReadFile(filepath.Join(destDir, "tasks.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "<h2>Просрочено</h2>")
+
+	content, err = os.ReadFile(filepath.Join(destDir, "tasks.json"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"text": "задача"`)
+}
+
 func TestConverterDirectory_WithNonMdFiles(t *testing.T) {
 	sut := NewConverter()
 
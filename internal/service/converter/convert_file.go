@@ -3,57 +3,67 @@ package converter
 import (
 	"bytes"
 	"fmt"
-	"github.com/russross/blackfriday/v2"
-	"gopkg.in/yaml.v3"
+	"html/template"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/page"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
 	log "github.com/sirupsen/logrus"
 )
 
-type FrontMatter struct {
-	Date   string   `yaml:"date"`
-	Author string   `yaml:"author"`
-	Tags   []string `yaml:"tags"`
-	Closed bool     `yaml:"closed"`
-}
-
 func (c *Converter) ConvertFile(filePath, srcDir, destDir string) error {
-	content, err := os.ReadFile(filePath)
+	note, err := vault.ParseNote(filePath, srcDir, c.noteOpts...)
 	if err != nil {
-		log.Errorf("Не удалось прочитать файл %s: %v", filePath, err)
-		return fmt.Errorf("не удалось прочитать файл: %v", err)
+		log.Errorf("Не удалось разобрать файл %s: %v", filePath, err)
+		return err
 	}
-	fm, mdContent, err := c.splitFrontMatter(content)
+	fm := note.FrontMatter
+
+	ctx := c.newContext(srcDir, note)
+	htmlContent, err := c.renderMarkdown(ctx, note.Body)
 	if err != nil {
-		log.Errorf("Ошибка при разборе FrontMatter для файла %s: %v", filePath, err)
-		return fmt.Errorf("ошибка при разборе FrontMatter: %v", err)
+		return err
 	}
+	c.remember(note.RelPath, htmlContent)
 
-	htmlContent := blackfriday.Run(mdContent)
-
+	var head strings.Builder
 	if len(fm.Date) > 0 || len(fm.Author) > 0 || len(fm.Tags) > 0 {
-		meta := fmt.Sprintf(
+		fmt.Fprintf(&head,
 			"<!-- Date: %s | Author: %s | Tags: %s | Closed: %t -->\n",
 			fm.Date, fm.Author, strings.Join(fm.Tags, ", "), fm.Closed,
 		)
-		htmlContent = append([]byte(meta), htmlContent...)
+	}
+	for _, h := range ctx.head {
+		head.WriteString(h)
 	}
 
-	// Определение относительного пути к файлу из исходной директории к целевой директории
-	relPath, err := filepath.Rel(srcDir, filePath)
-	if err != nil {
-		log.Errorf("Не удалось определить относительный путь для файла %s: %v", filePath, err)
-		return fmt.Errorf("не удалось определить относительный путь: %v", err)
+	// Заметка выводит собственные заголовки, поэтому заголовок страницы не нужен
+	var buf bytes.Buffer
+	if err := page.Render(&buf, page.Data{
+		Title:       note.Title(),
+		Body:        template.HTML(htmlContent),
+		Head:        template.HTML(head.String()),
+		HideHeading: true,
+		TOC:         ctx.TOC,
+	}); err != nil {
+		log.Errorf("Не удалось отрисовать страницу для файла %s: %v", filePath, err)
+		return err
 	}
 
-	// Замена расширения на .html с сохранением названия исходного файла
-	htmlFileName := fmt.Sprintf("%s.html", filepath.Base(relPath[:len(relPath)-len(filepath.Ext(relPath))]))
-	htmlFilePath := filepath.Join(destDir, htmlFileName)
+	// Целевую директорию создаёт ConvertDirectory, здесь — только поддиректории страниц вида 2024/12/09/index.html
+	htmlFilePath := filepath.Join(destDir, filepath.FromSlash(c.pageURL(srcDir, note.RelPath)))
+	if dir := filepath.Dir(htmlFilePath); dir != filepath.Clean(destDir) {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			log.Errorf("Не удалось создать директорию для %s: %v", htmlFilePath, err)
+			return fmt.Errorf("не удалось создать директорию для %s: %v", htmlFilePath, err)
+		}
+	}
 
-	// Проверка существования HTML-файла
-	if info, err := os.Stat(htmlFilePath); err == nil {
+	// Проверка существования HTML-файла. Результаты запросов Dataview зависят
+	// от других заметок, поэтому такие файлы пересобираются всегда
+	if info, err := os.Stat(htmlFilePath); err == nil && !ctx.Dynamic {
 		// Получение времени последнего изменения исходного файла
 		srcInfo, err := os.Stat(filePath)
 		if err != nil {
@@ -71,7 +81,7 @@ func (c *Converter) ConvertFile(filePath, srcDir, destDir string) error {
 	}
 
 	// Запись HTML содержимого в файл | Создание файла если не было | Переписывание если был
-	if err := os.WriteFile(htmlFilePath, htmlContent, 0644); err != nil {
+	if err := os.WriteFile(htmlFilePath, buf.Bytes(), 0644); err != nil {
 		log.Errorf("Не удалось записать HTML файл %s: %v", htmlFilePath, err)
 		return fmt.Errorf("не удалось записать HTML файл: %v", err)
 	}
@@ -83,18 +93,12 @@ func (c *Converter) ConvertFile(filePath, srcDir, destDir string) error {
 	return nil
 }
 
-func (c *Converter) splitFrontMatter(content []byte) (*FrontMatter, []byte, error) {
-	delimiter := []byte("---")
-
-	parts := bytes.SplitN(content, delimiter, 3)
-	if len(parts) < 3 {
-		return &FrontMatter{}, content, nil
-	}
-
-	var fm FrontMatter
-	if err := yaml.Unmarshal(parts[1], &fm); err != nil {
-		return nil, nil, fmt.Errorf("не удалось распарсить FrontMatter: %v", err)
-	}
+// RenderNote возвращает HTML тела заметки так же, как при конвертации, но без записи на диск
+func (c *Converter) RenderNote(note *vault.Note, srcDir string) ([]byte, error) {
+	return c.renderMarkdown(c.newContext(srcDir, note), note.Body)
+}
 
-	return &fm, parts[2], nil
+// htmlFileName заменяет расширение на .html с сохранением названия исходного файла
+func htmlFileName(relPath string) string {
+	return fmt.Sprintf("%s.html", filepath.Base(relPath[:len(relPath)-len(filepath.Ext(relPath))]))
 }
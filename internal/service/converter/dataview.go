@@ -0,0 +1,120 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/dataview"
+	log "github.com/sirupsen/logrus"
+)
+
+// Запросы заменяются заглушками до рендеринга Markdown и подставляются после,
+// чтобы рендерер Markdown не обрабатывал готовый HTML
+const dataviewPlaceholder = "DATAVIEW-BLOCK-%d"
+
+var dataviewPlaceholderRe = regexp.MustCompile(`(?:<p>)?DATAVIEW-BLOCK-(\d+)(?:</p>)?`)
+
+func dataviewPlugin() Plugin {
+	return Plugin{
+		Name: "dataview",
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			md, queries := extractDataviewBlocks(md)
+			if len(queries) > 0 {
+				ctx.Set("dataview", queries)
+				ctx.Dynamic = true
+			}
+			return md, nil
+		},
+		PostRender: func(ctx *Context, html []byte) ([]byte, error) {
+			queries, _ := ctx.Get("dataview").([]string)
+			if len(queries) == 0 {
+				return html, nil
+			}
+			v, err := ctx.Vault()
+			if err != nil {
+				log.Errorf("Не удалось загрузить заметки для запросов Dataview: %v", err)
+				return nil, fmt.Errorf("не удалось загрузить заметки для запросов Dataview: %v", err)
+			}
+			return renderDataviewBlocks(html, queries, dataview.NewEngine(v, ctx.PageURL, ctx.Now)), nil
+		},
+	}
+}
+
+// extractDataviewBlocks вырезает блоки ```dataview, возвращая текст с заглушками и сами запросы.
+// Блоки внутри других блоков кода не трогаются, незакрытый блок остаётся как есть
+func extractDataviewBlocks(md []byte) ([]byte, []string) {
+	lines := strings.Split(string(md), "\n")
+	var out []string
+	var queries []string
+
+	outer := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if outer != "" {
+			if isClosingFence(trimmed, outer) {
+				outer = ""
+			}
+			out = append(out, line)
+			continue
+		}
+
+		marker := fenceMarker(trimmed)
+		if marker == "" {
+			out = append(out, line)
+			continue
+		}
+		if strings.TrimSpace(strings.TrimPrefix(trimmed, marker)) != "dataview" {
+			outer = marker
+			out = append(out, line)
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && !isClosingFence(strings.TrimSpace(lines[end]), marker) {
+			end++
+		}
+		if end == len(lines) {
+			// Незакрытый блок по правилам Markdown тянется до конца заметки
+			out = append(out, lines[i:]...)
+			break
+		}
+
+		// Отступ сохраняется, чтобы запрос внутри списка не разрывал его
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		out = append(out, "", indent+fmt.Sprintf(dataviewPlaceholder, len(queries)), "")
+		queries = append(queries, strings.Join(lines[i+1:end], "\n"))
+		i = end
+	}
+
+	if len(queries) == 0 {
+		return md, nil
+	}
+	return []byte(strings.Join(out, "\n")), queries
+}
+
+// fenceMarker возвращает открывающую последовательность ``` или ~~~ (три символа и больше)
+func fenceMarker(line string) string {
+	if !strings.HasPrefix(line, "```") && !strings.HasPrefix(line, "~~~") {
+		return ""
+	}
+	return line[:len(line)-len(strings.TrimLeft(line, line[:1]))]
+}
+
+// isClosingFence проверяет, что строка закрывает блок, открытый marker
+func isClosingFence(line, marker string) bool {
+	return strings.HasPrefix(line, marker) && strings.Trim(line, marker[:1]) == ""
+}
+
+// renderDataviewBlocks подставляет результаты запросов на место заглушек
+func renderDataviewBlocks(html []byte, queries []string, engine *dataview.Engine) []byte {
+	return dataviewPlaceholderRe.ReplaceAllFunc(html, func(match []byte) []byte {
+		i, err := strconv.Atoi(string(dataviewPlaceholderRe.FindSubmatch(match)[1]))
+		if err != nil || i >= len(queries) {
+			return match
+		}
+		return []byte(engine.RenderBlock(queries[i]))
+	})
+}
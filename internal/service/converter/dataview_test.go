@@ -0,0 +1,93 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractDataviewBlocks(t *testing.T) {
+	md := "# Заметка\n```dataview\nLIST\nFROM #daily\n```\nТекст\n```go\nfmt.Println()\n```\n~~~dataview\nTASK\n~~~\n"
+
+	out, queries := extractDataviewBlocks([]byte(md))
+
+	require.Equal(t, []string{"LIST\nFROM #daily", "TASK"}, queries)
+	require.Contains(t, string(out), "\nDATAVIEW-BLOCK-0\n")
+	require.Contains(t, string(out), "\nDATAVIEW-BLOCK-1\n")
+	require.Contains(t, string(out), "```go\nfmt.Println()\n```")
+	require.NotContains(t, string(out), "FROM #daily")
+}
+
+func TestExtractDataviewBlocks_Untouched(t *testing.T) {
+	testCases := []struct {
+		name string
+		md   string
+	}{
+		{
+			name: "незакрытый блок",
+			md:   "# Заметка\n```dataview\nLIST\nТекст после\n",
+		},
+		{
+			name: "пример внутри блока кода",
+			md:   "````markdown\n```dataview\nLIST\n```\n````\n~~~\n```dataview\nTASK\n```\n~~~\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, queries := extractDataviewBlocks([]byte(tc.md))
+
+			require.Empty(t, queries)
+			require.Equal(t, tc.md, string(out))
+		})
+	}
+}
+
+func TestExtractDataviewBlocks_KeepsIndent(t *testing.T) {
+	md := "- Задачи\n  ```dataview\n  TASK\n  ```\n- Дальше\n"
+
+	out, queries := extractDataviewBlocks([]byte(md))
+
+	require.Equal(t, []string{"  TASK"}, queries)
+	require.Equal(t, "- Задачи\n\n  DATAVIEW-BLOCK-0\n\n- Дальше\n", string(out))
+}
+
+func TestConvertFile_DataviewQuery(t *testing.T) {
+	sut := NewConverter()
+
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "2024-12-09.md"), []byte("---\ndate: 2024-12-09\ntags:\n  - \"#daily\"\n---\n# День"), 0644))
+
+	indexPath := filepath.Join(srcDir, "index.md")
+	require.NoError(t, os.WriteFile(indexPath, []byte("# Индекс\n\n```dataview\nLIST FROM #daily\n```\n"), 0644))
+
+	err = sut.ConvertFile(indexPath, srcDir, destDir)
+	require.NoError(t, err)
+
+	htmlData, err := os.ReadFile(filepath.Join(destDir, "index.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(htmlData), `<li><a class="internal-link" href="2024-12-09.html">2024-12-09</a></li>`)
+	require.NotContains(t, string(htmlData), "DATAVIEW-BLOCK")
+	require.NotContains(t, string(htmlData), "<code")
+
+	// Файл с запросом пересобирается, даже если сам он не изменился
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "2024-12-10.md"), []byte("---\ntags:\n  - \"#daily\"\n---\n"), 0644))
+	sut.resetVault()
+
+	err = sut.ConvertFile(indexPath, srcDir, destDir)
+	require.NoError(t, err)
+
+	htmlData, err = os.ReadFile(filepath.Join(destDir, "index.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(htmlData), "2024-12-10.html")
+}
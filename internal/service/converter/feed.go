@@ -0,0 +1,263 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/feed"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultFeedLimit = 20
+	// Длина краткого содержания записи без поля summary
+	feedSummaryLength = 300
+	// Ленты по тегам: tags/<тег>/feed.xml
+	tagFeedsDir = "tags"
+)
+
+var (
+	// Ссылки и картинки в HTML заметки, которые в ленте нужно сделать абсолютными
+	linkAttrRe = regexp.MustCompile(`(\s(?:href|src)=")([^"]*)(")`)
+	// Ссылки-якоря заголовков нужны только на странице
+	headingAnchorRe = regexp.MustCompile(` ?<a class="heading-anchor"[^>]*>#</a>`)
+)
+
+// FeedOptions — настройки лент RSS, Atom и JSON Feed
+type FeedOptions struct {
+	Enabled bool
+	// Число записей в каждой ленте; 0 — DefaultFeedLimit
+	Limit int
+	// Краткое содержание вместо полного HTML заметки
+	Summary bool
+	// Отдельные ленты для каждого тега
+	Tags bool
+}
+
+// WithFeeds включает ленты feed.xml, atom.xml и feed.json
+func WithFeeds(opts FeedOptions) Option {
+	return func(c *Converter) {
+		c.feeds = opts
+	}
+}
+
+// feedFormats — файлы одной ленты и функции их записи
+var feedFormats = []struct {
+	file  string
+	mime  string
+	write func(w io.Writer, f *feed.Feed, self string) error
+}{
+	{feed.RSSFileName, "application/rss+xml", feed.WriteRSS},
+	{feed.AtomFileName, "application/atom+xml", feed.WriteAtom},
+	{feed.JSONFileName, "application/feed+json", feed.WriteJSON},
+}
+
+func feedPlugin(site SiteOptions, opts FeedOptions) Plugin {
+	if !opts.Enabled {
+		return Plugin{Name: "feeds"}
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultFeedLimit
+	}
+	if site.Title == "" {
+		site.Title = "Заметки"
+	}
+
+	return Plugin{
+		Name: "feeds",
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			// Ссылки для автоматического обнаружения лент читалками
+			for _, f := range feedFormats {
+				ctx.AddHead(fmt.Sprintf("<link rel=\"alternate\" type=\"%s\" title=\"%s\" href=\"%s\">\n",
+					f.mime, html.EscapeString(site.Title), ctx.AssetURL(f.file)))
+			}
+			return md, nil
+		},
+		AfterRun: func(run *Run) ([]Page, error) {
+			if site.BaseURL == "" {
+				log.Warn("Не задан site.base_url: ссылки в лентах будут относительными")
+			}
+			v, err := run.Vault()
+			if err != nil {
+				log.Errorf("Не удалось загрузить заметки: %v", err)
+				return nil, fmt.Errorf("не удалось загрузить заметки: %v", err)
+			}
+
+			notes := feedNotes(v)
+			items := make(map[*vault.Note]feed.Item)
+			item := func(note *vault.Note) (feed.Item, error) {
+				if it, ok := items[note]; ok {
+					return it, nil
+				}
+				it, err := feedItem(run, site, opts, note)
+				items[note] = it
+				return it, err
+			}
+
+			pages, err := buildFeed(run, site, opts, "", site.Title, notes, item)
+			if err != nil {
+				return nil, err
+			}
+			if !opts.Tags {
+				return pages, nil
+			}
+
+			byTag := make(map[string][]*vault.Note)
+			for _, note := range notes {
+				for _, tag := range note.Tags {
+					byTag[tag] = append(byTag[tag], note)
+				}
+			}
+			tags := make([]string, 0, len(byTag))
+			for tag := range byTag {
+				tags = append(tags, tag)
+			}
+			sort.Strings(tags)
+			dirs := tagFeedDirs(tags)
+			for _, tag := range tags {
+				tagPages, err := buildFeed(run, site, opts, dirs[tag], site.Title+" — "+tag, byTag[tag], item)
+				if err != nil {
+					return nil, err
+				}
+				pages = append(pages, tagPages...)
+			}
+			return pages, nil
+		},
+	}
+}
+
+// feedNotes возвращает опубликованные заметки с датой, новые первыми
+func feedNotes(v *vault.Vault) []*vault.Note {
+	var notes []*vault.Note
+	for _, note := range v.Notes {
+		if _, ok := note.Date(); ok && published(note) {
+			notes = append(notes, note)
+		}
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		di, _ := notes[i].Date()
+		dj, _ := notes[j].Date()
+		return di.After(dj)
+	})
+	return notes
+}
+
+// buildFeed собирает ленту из первых заметок и возвращает её файлы в директории dir
+func buildFeed(run *Run, site SiteOptions, opts FeedOptions, dir, title string, notes []*vault.Note, item func(*vault.Note) (feed.Item, error)) ([]Page, error) {
+	f := &feed.Feed{
+		Title:       title,
+		Description: site.Description,
+		HomeURL:     site.absURL(""),
+		Author:      site.Author,
+		Updated:     run.Now,
+	}
+	for i, note := range notes {
+		if i == opts.Limit {
+			break
+		}
+		it, err := item(note)
+		if err != nil {
+			return nil, err
+		}
+		f.Items = append(f.Items, it)
+	}
+	if len(f.Items) > 0 {
+		f.Updated = f.Items[0].Date
+	}
+
+	var pages []Page
+	for _, format := range feedFormats {
+		file := path.Join(dir, format.file)
+		var buf bytes.Buffer
+		if err := format.write(&buf, f, site.absURL(escapePath(file))); err != nil {
+			log.Errorf("Не удалось записать ленту %s: %v", file, err)
+			return nil, fmt.Errorf("не удалось записать ленту %s: %v", file, err)
+		}
+		pages = append(pages, Page{Path: file, Content: buf.Bytes()})
+	}
+	return pages, nil
+}
+
+func feedItem(run *Run, site SiteOptions, opts FeedOptions, note *vault.Note) (feed.Item, error) {
+	date, _ := note.Date()
+	pageURL := site.absURL(escapePath(run.PageURL(note.RelPath)))
+
+	rendered, err := run.RenderNote(note)
+	if err != nil {
+		log.Errorf("Не удалось отрисовать заметку %s для ленты: %v", note.RelPath, err)
+		return feed.Item{}, fmt.Errorf("не удалось отрисовать заметку %s для ленты: %v", note.RelPath, err)
+	}
+	content := headingAnchorRe.ReplaceAllString(string(rendered), "")
+
+	it := feed.Item{
+		URL:     pageURL,
+		Title:   note.Title(),
+		Author:  site.Author,
+		Date:    date,
+		Summary: frontMatterString(note, "summary"),
+	}
+	if note.FrontMatter != nil && note.FrontMatter.Author != "" {
+		it.Author = note.FrontMatter.Author
+	}
+	for _, tag := range note.Tags {
+		it.Tags = append(it.Tags, strings.TrimPrefix(tag, "#"))
+	}
+	if it.Summary == "" {
+		it.Summary = feed.Summary(content, feedSummaryLength)
+	}
+	if !opts.Summary {
+		it.Content = absoluteLinks(content, pageURL)
+	}
+	return it, nil
+}
+
+// absoluteLinks переписывает относительные ссылки HTML заметки относительно адреса её страницы,
+// потому что читалка показывает запись вне сайта
+func absoluteLinks(content, pageURL string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil || !base.IsAbs() {
+		return content
+	}
+	return linkAttrRe.ReplaceAllStringFunc(content, func(match string) string {
+		m := linkAttrRe.FindStringSubmatch(match)
+		ref, err := url.Parse(html.UnescapeString(m[2]))
+		if err != nil || ref.IsAbs() {
+			return match
+		}
+		return m[1] + html.EscapeString(base.ResolveReference(ref).String()) + m[3]
+	})
+}
+
+// tagFeedDirs раздаёт тегам директории лент. Теги с одинаковым слагом (#c и #c++)
+// получают числовой суффикс, как повторяющиеся заголовки, чтобы ленты не перезаписывали друг друга
+func tagFeedDirs(tags []string) map[string]string {
+	dirs := make(map[string]string, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		dir := tagFeedDir(tag)
+		unique := dir
+		for i := 1; seen[unique]; i++ {
+			unique = fmt.Sprintf("%s-%d", dir, i)
+		}
+		seen[unique] = true
+		dirs[tag] = unique
+	}
+	return dirs
+}
+
+// tagFeedDir — директория лент тега: #project/alpha → tags/project/alpha
+func tagFeedDir(tag string) string {
+	parts := strings.Split(strings.TrimPrefix(tag, "#"), "/")
+	for i, part := range parts {
+		parts[i] = vault.Slug(part)
+	}
+	return path.Join(tagFeedsDir, path.Join(parts...))
+}
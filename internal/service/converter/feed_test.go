@@ -0,0 +1,168 @@
+package converter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedPlugin(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644))
+	}
+	write("2024-12-09.md", "---\ndate: 2024-12-09\nauthor: Иван\ntags: ['#daily']\n---\n# Понедельник\nСм. [[2024-12-10]] и ![[photo.png]]\n")
+	write("2024-12-10.md", "---\ndate: 2024-12-10\ntags: ['#daily', '#go']\n---\n# Вторник\nТекст\n")
+	write("secret.md", "---\ndate: 2024-12-11\npublish: false\n---\n# Личное\n")
+	write("idea.md", "# Без даты\n")
+
+	conv := NewConverter(
+		WithSite(SiteOptions{BaseURL: "https://notes.example.com/daily/", Title: "Дневник", Author: "Команда"}),
+		WithFeeds(FeedOptions{Enabled: true, Tags: true}),
+	)
+	require.NoError(t, conv.ConvertDirectory(srcDir, destDir))
+
+	rss, err := os.ReadFile(filepath.Join(destDir, "feed.xml"))
+	require.NoError(t, err)
+	require.Contains(t, string(rss), `<atom:link href="https://notes.example.com/daily/feed.xml" rel="self" type="application/rss+xml"></atom:link>`)
+	require.Contains(t, string(rss), `<title>Вторник</title>`)
+	require.Contains(t, string(rss), `<link>https://notes.example.com/daily/2024-12-09.html</link>`)
+	require.Contains(t, string(rss), `<dc:creator>Иван</dc:creator>`)
+	require.Contains(t, string(rss), `<category>go</category>`)
+	// Ссылки внутри записи абсолютные
+	require.Contains(t, string(rss), `href=&#34;https://notes.example.com/daily/2024-12-10.html&#34;`)
+	require.Contains(t, string(rss), `src=&#34;https://notes.example.com/daily/photo.png&#34;`)
+	require.NotContains(t, string(rss), "Личное")
+	require.NotContains(t, string(rss), "Без даты")
+	// Новые записи первыми
+	require.Less(t, strings.Index(string(rss), "Вторник"), strings.Index(string(rss), "Понедельник"))
+
+	atom, err := os.ReadFile(filepath.Join(destDir, "atom.xml"))
+	require.NoError(t, err)
+	require.Contains(t, string(atom), `<updated>2024-12-10T00:00:00Z</updated>`)
+	require.Contains(t, string(atom), `<category term="daily"></category>`)
+	require.Contains(t, string(atom), `<content type="html">`)
+
+	var jsonFeed struct {
+		FeedURL string `json:"feed_url"`
+		Items   []struct {
+			ID      string   `json:"id"`
+			Tags    []string `json:"tags"`
+			Summary string   `json:"summary"`
+		} `json:"items"`
+	}
+	content, err := os.ReadFile(filepath.Join(destDir, "feed.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &jsonFeed))
+	require.Equal(t, "https://notes.example.com/daily/feed.json", jsonFeed.FeedURL)
+	require.Len(t, jsonFeed.Items, 2)
+	require.Equal(t, "https://notes.example.com/daily/2024-12-10.html", jsonFeed.Items[0].ID)
+	require.Equal(t, []string{"daily", "go"}, jsonFeed.Items[0].Tags)
+	require.Equal(t, "Вторник Текст", jsonFeed.Items[0].Summary)
+
+	goFeed, err := os.ReadFile(filepath.Join(destDir, "tags", "go", "feed.xml"))
+	require.NoError(t, err)
+	require.Contains(t, string(goFeed), "<title>Дневник — #go</title>")
+	require.Contains(t, string(goFeed), "Вторник")
+	require.NotContains(t, string(goFeed), "Понедельник")
+	require.FileExists(t, filepath.Join(destDir, "tags", "daily", "atom.xml"))
+
+	page, err := os.ReadFile(filepath.Join(destDir, "2024-12-09.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(page), `<link rel="alternate" type="application/rss+xml" title="Дневник" href="feed.xml">`)
+}
+
+func TestFeedPlugin_RendersOnce(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a.md"), []byte("---\ndate: 2024-12-09\ntags: ['#daily', '#go']\n---\nТекст\n"), 0644))
+
+	renders := make(map[string]int)
+	counter := Plugin{
+		Name: "counter",
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			renders[ctx.Note.Name]++
+			return md, nil
+		},
+	}
+	conv := NewConverter(WithPlugins(counter), WithFeeds(FeedOptions{Enabled: true, Tags: true}))
+
+	// Второй запуск пропускает неизменённую страницу, но ленты всё равно получают её HTML
+	for i := 0; i < 2; i++ {
+		require.NoError(t, conv.ConvertDirectory(srcDir, destDir))
+	}
+
+	require.Equal(t, map[string]int{"a": 2}, renders)
+	goFeed, err := os.ReadFile(filepath.Join(destDir, "tags", "go", "feed.xml"))
+	require.NoError(t, err)
+	require.Contains(t, string(goFeed), "Текст")
+}
+
+func TestTagFeedDirs(t *testing.T) {
+	dirs := tagFeedDirs([]string{"#c", "#c++", "#c-1", "#project/alpha"})
+
+	require.Equal(t, map[string]string{
+		"#c":             "tags/c",
+		"#c++":           "tags/c-1",
+		"#c-1":           "tags/c-1-1",
+		"#project/alpha": "tags/project/alpha",
+	}, dirs)
+}
+
+func TestFeedPlugin_Summary(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a.md"), []byte("---\ndate: 2024-12-09\nsummary: Коротко\n---\nДлинный текст\n"), 0644))
+
+	require.NoError(t, NewConverter(WithFeeds(FeedOptions{Enabled: true, Summary: true})).ConvertDirectory(srcDir, destDir))
+
+	atom, err := os.ReadFile(filepath.Join(destDir, "atom.xml"))
+	require.NoError(t, err)
+	require.Contains(t, string(atom), `<summary type="text">Коротко</summary>`)
+	require.NotContains(t, string(atom), "Длинный текст")
+	require.Contains(t, string(atom), `<link href="a.html" rel="alternate" type="text/html"></link>`)
+	require.NoDirExists(t, filepath.Join(destDir, "tags"))
+}
+
+func TestFeedPlugin_Disabled(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a.md"), []byte("---\ndate: 2024-12-09\n---\nТекст\n"), 0644))
+
+	require.NoError(t, NewConverter().ConvertDirectory(srcDir, destDir))
+
+	require.NoFileExists(t, filepath.Join(destDir, "feed.xml"))
+	page, err := os.ReadFile(filepath.Join(destDir, "a.html"))
+	require.NoError(t, err)
+	require.NotContains(t, string(page), "application/rss+xml")
+}
@@ -0,0 +1,70 @@
+package converter
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	lineFieldRe    = regexp.MustCompile(`^(\s*(?:[-*+]\s+)?)([\p{L}\p{N}_][\p{L}\p{N}_ /-]*?)::\s*(.*?)\s*$`)
+	bracketFieldRe = regexp.MustCompile(`([\[(])([\p{L}\p{N}_][\p{L}\p{N}_ /-]*?)::\s*([^\])]*?)\s*[\])]`)
+)
+
+func inlineFieldsPlugin() Plugin {
+	return Plugin{
+		Name: "inline-fields",
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			return renderInlineFields(md), nil
+		},
+	}
+}
+
+// renderInlineFields превращает поля key:: value в оформленные спаны.
+// Поле на всю строку выводится отдельной строкой, поле в скобках — внутри текста
+func renderInlineFields(md []byte) []byte {
+	lines := strings.Split(string(md), "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		if m := lineFieldRe.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + fieldSpan("inline-field inline-field-line", m[2], m[3])
+			continue
+		}
+		lines[i] = replaceOutsideCode(line, func(text string) string {
+			return bracketFieldRe.ReplaceAllStringFunc(text, func(match string) string {
+				m := bracketFieldRe.FindStringSubmatch(match)
+				// (key:: value) скрывает имя поля, как в Obsidian
+				if m[1] == "(" {
+					return fmt.Sprintf(`<span class="inline-field inline-field-hidden-key" title="%s">%s</span>`,
+						html.EscapeString(m[2]), m[3])
+				}
+				return fieldSpan("inline-field", m[2], m[3])
+			})
+		})
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func fieldSpan(class, key, value string) string {
+	return fmt.Sprintf(`<span class="%s"><span class="inline-field-key">%s</span><span class="inline-field-value">%s</span></span>`,
+		class, html.EscapeString(strings.TrimSpace(key)), value)
+}
+
+// replaceOutsideCode применяет replace только к частям строки вне `кода`
+func replaceOutsideCode(line string, replace func(string) string) string {
+	parts := strings.Split(line, "`")
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = replace(parts[i])
+	}
+	return strings.Join(parts, "`")
+}
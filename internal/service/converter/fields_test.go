@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderInlineFields(t *testing.T) {
+	testCases := []struct {
+		name     string
+		markdown string
+		expected string
+	}{
+		{
+			name:     "Поле на всю строку",
+			markdown: "mood:: 7",
+			expected: `<span class="inline-field inline-field-line"><span class="inline-field-key">mood</span><span class="inline-field-value">7</span></span>`,
+		},
+		{
+			name:     "Поле в пункте списка",
+			markdown: "- sleep:: 6.5h",
+			expected: `- <span class="inline-field inline-field-line"><span class="inline-field-key">sleep</span><span class="inline-field-value">6.5h</span></span>`,
+		},
+		{
+			name:     "Поле в квадратных скобках",
+			markdown: "Сегодня [energy:: high]!",
+			expected: `Сегодня <span class="inline-field"><span class="inline-field-key">energy</span><span class="inline-field-value">high</span></span>!`,
+		},
+		{
+			name:     "Поле в круглых скобках скрывает ключ",
+			markdown: "Вес (weight:: 70)",
+			expected: `Вес <span class="inline-field inline-field-hidden-key" title="weight">70</span>`,
+		},
+		{
+			name:     "Код не изменяется",
+			markdown: "`[a:: b]`\n```\nkey:: value\n```",
+			expected: "`[a:: b]`\n```\nkey:: value\n```",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, string(renderInlineFields([]byte(tc.markdown))))
+		})
+	}
+}
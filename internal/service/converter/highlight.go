@@ -0,0 +1,223 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/page"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultHighlightTheme = "github"
+
+	// Блоки кода подсвечиваются отдельно от рендеринга, поэтому работают с любым рендерером
+	codePlaceholder = "CODE-BLOCK-%d"
+)
+
+var (
+	codePlaceholderRe = regexp.MustCompile(`(?:<p>)?CODE-BLOCK-(\d+)(?:</p>)?`)
+	// Открывающая граница блока: отступ или цитата, ``` или ~~~ и строка информации
+	fenceOpenRe = regexp.MustCompile("^(\\s*(?:>\\s?)*)(`{3,}|~{3,})\\s*([^`]*)$")
+	// Подсвечиваемые строки: {3-5} или {1,4-6}
+	lineRangesRe = regexp.MustCompile(`\{([\d\s,-]+)\}`)
+)
+
+// HighlightOptions — настройки подсветки кода
+type HighlightOptions struct {
+	// Тема chroma: github, monokai, dracula и т.д.
+	Theme       string
+	LineNumbers bool
+}
+
+// WithHighlight задаёт тему и нумерацию строк подсветки кода
+func WithHighlight(opts HighlightOptions) Option {
+	return func(c *Converter) {
+		c.highlight = opts
+	}
+}
+
+type codeBlock struct {
+	lang   string
+	code   string
+	ranges [][2]int
+}
+
+func highlightPlugin(opts HighlightOptions) Plugin {
+	style := styles.Get(opts.Theme)
+	if _, ok := styles.Registry[opts.Theme]; !ok {
+		if opts.Theme != "" {
+			log.Warnf("Неизвестная тема подсветки %s, используется %s", opts.Theme, DefaultHighlightTheme)
+		}
+		style = styles.Get(DefaultHighlightTheme)
+	}
+
+	var css bytes.Buffer
+	if err := chromahtml.New(chromahtml.WithClasses(true)).WriteCSS(&css, style); err != nil {
+		log.Errorf("Не удалось сформировать CSS подсветки: %v", err)
+	}
+	head := "<style>\n" + css.String() + "</style>\n" + string(page.CopyButtonScript)
+
+	return Plugin{
+		Name: "highlight",
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			md, blocks := extractCodeBlocks(md)
+			if len(blocks) > 0 {
+				ctx.Set("highlight", blocks)
+				ctx.AddHead(head)
+			}
+			return md, nil
+		},
+		PostRender: func(ctx *Context, htmlContent []byte) ([]byte, error) {
+			blocks, _ := ctx.Get("highlight").([]codeBlock)
+			if len(blocks) == 0 {
+				return htmlContent, nil
+			}
+			var renderErr error
+			out := codePlaceholderRe.ReplaceAllFunc(htmlContent, func(match []byte) []byte {
+				i, err := strconv.Atoi(string(codePlaceholderRe.FindSubmatch(match)[1]))
+				if err != nil || i >= len(blocks) {
+					return match
+				}
+				rendered, err := blocks[i].html(style, opts.LineNumbers)
+				if err != nil && renderErr == nil {
+					renderErr = err
+				}
+				return []byte(rendered)
+			})
+			return out, renderErr
+		},
+	}
+}
+
+// extractCodeBlocks вырезает блоки кода, в том числе внутри списков и цитат
+func extractCodeBlocks(md []byte) ([]byte, []codeBlock) {
+	return extractFences(md, codePlaceholder, func(string) bool { return true })
+}
+
+// extractFences заменяет заглушками блоки кода, язык которых подходит под match;
+// остальные блоки остаются в тексте без изменений
+func extractFences(md []byte, placeholder string, match func(lang string) bool) ([]byte, []codeBlock) {
+	lines := strings.Split(string(md), "\n")
+	var out []string
+	var blocks []codeBlock
+
+	for i := 0; i < len(lines); i++ {
+		m := fenceOpenRe.FindStringSubmatch(lines[i])
+		if m == nil {
+			out = append(out, lines[i])
+			continue
+		}
+		prefix, fence, info := m[1], m[2], strings.TrimSpace(m[3])
+
+		end := -1
+		var code []string
+		for j := i + 1; j < len(lines); j++ {
+			line := stripPrefix(lines[j], prefix)
+			if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				end = j
+				break
+			}
+			code = append(code, line)
+		}
+		// Незакрытый блок оставляем рендереру
+		if end < 0 {
+			out = append(out, lines[i])
+			continue
+		}
+
+		block := codeBlock{code: strings.Join(code, "\n")}
+		if r := lineRangesRe.FindStringSubmatch(info); r != nil {
+			block.ranges = parseLineRanges(r[1])
+			info = strings.TrimSpace(strings.Replace(info, r[0], "", 1))
+		}
+		if fields := strings.Fields(info); len(fields) > 0 {
+			block.lang = fields[0]
+		}
+
+		if !match(block.lang) {
+			out = append(out, lines[i:end+1]...)
+			i = end
+			continue
+		}
+
+		quote := strings.TrimRight(prefix, " \t")
+		out = append(out, quote, prefix+fmt.Sprintf(placeholder, len(blocks)), quote)
+		blocks = append(blocks, block)
+		i = end
+	}
+
+	if len(blocks) == 0 {
+		return md, nil
+	}
+	return []byte(strings.Join(out, "\n")), blocks
+}
+
+// stripPrefix убирает из строки блока отступ или маркеры цитаты открывающей строки
+func stripPrefix(line, prefix string) string {
+	if strings.HasPrefix(line, prefix) {
+		return line[len(prefix):]
+	}
+	if trimmed := strings.TrimRight(prefix, " \t"); strings.HasPrefix(line, trimmed) {
+		return line[len(trimmed):]
+	}
+	return strings.TrimLeft(line, " \t")
+}
+
+func parseLineRanges(s string) [][2]int {
+	var ranges [][2]int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			continue
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+func (b codeBlock) html(style *chroma.Style, lineNumbers bool) (string, error) {
+	lexer := lexers.Get(b.lang)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	iterator, err := lexer.Tokenise(nil, b.code+"\n")
+	if err != nil {
+		return "", fmt.Errorf("не удалось разобрать блок кода %s: %v", b.lang, err)
+	}
+
+	formatter := chromahtml.New(
+		chromahtml.WithClasses(true),
+		chromahtml.WithLineNumbers(lineNumbers),
+		chromahtml.HighlightLines(b.ranges),
+	)
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, style, iterator); err != nil {
+		return "", fmt.Errorf("не удалось подсветить блок кода %s: %v", b.lang, err)
+	}
+
+	lang := ""
+	if b.lang != "" {
+		lang = fmt.Sprintf(` data-lang="%s"`, html.EscapeString(b.lang))
+	}
+	return fmt.Sprintf("<div class=\"highlight\"%s>%s</div>\n", lang, buf.String()), nil
+}
@@ -0,0 +1,50 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractCodeBlocks(t *testing.T) {
+	md := "Текст\n```go {2,4-5}\nfunc main() {}\n```\n- пункт\n  ~~~\n  code\n  ~~~\n> ```\n> quoted\n> ```\n```\nне закрыт"
+
+	out, blocks := extractCodeBlocks([]byte(md))
+
+	require.Equal(t, []codeBlock{
+		{lang: "go", code: "func main() {}", ranges: [][2]int{{2, 2}, {4, 5}}},
+		{code: "code"},
+		{code: "quoted"},
+	}, blocks)
+	require.Equal(t, "Текст\n\nCODE-BLOCK-0\n\n- пункт\n\n  CODE-BLOCK-1\n\n>\n> CODE-BLOCK-2\n>\n```\nне закрыт", string(out))
+}
+
+func TestHighlightPlugin(t *testing.T) {
+	sut := NewConverter(WithHighlight(HighlightOptions{Theme: "monokai", LineNumbers: true}))
+	ctx := sut.newContext("", nil)
+
+	got, err := sut.renderMarkdown(ctx, []byte("```go {2}\npackage main\nfunc main() {}\n```\n\n> [!example]\n> ```python\n> print(1)\n> ```"))
+
+	require.NoError(t, err)
+	require.Contains(t, string(got), `<div class="highlight" data-lang="go"><pre class="chroma">`)
+	require.Contains(t, string(got), `<span class="line hl"><span class="ln">2</span><span class="cl"><span class="kd">func</span>`)
+	require.Contains(t, string(got), `<div class="highlight" data-lang="python">`)
+	require.NotContains(t, string(got), "CODE-BLOCK")
+
+	require.Len(t, ctx.head, 1)
+	require.Contains(t, ctx.head[0], "/* Background */ .bg { color: #f8f8f2; background-color: #272822; }")
+	require.Contains(t, ctx.head[0], `button.className = "copy-button"`)
+}
+
+func TestHighlightPlugin_UnknownLanguage(t *testing.T) {
+	sut := NewConverter()
+
+	got, err := sut.renderMarkdown(sut.newContext("", nil), []byte("```nosuchlang\n<b>\n```"))
+
+	require.NoError(t, err)
+	require.Contains(t, string(got), "&lt;b&gt;")
+}
+
+func TestParseLineRanges(t *testing.T) {
+	require.Equal(t, [][2]int{{1, 1}, {3, 5}}, parseLineRanges("1, 3-5, x"))
+}
@@ -0,0 +1,196 @@
+package converter
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/mathml"
+	log "github.com/sirupsen/logrus"
+)
+
+// Формулы вырезаются до рендеринга, иначе _ и * внутри них становятся курсивом
+const (
+	mathBlockPlaceholder  = "MATH-BLOCK-%d"
+	mathInlinePlaceholder = "MATH-INLINE-%d"
+)
+
+var (
+	mathBlockRe  = regexp.MustCompile(`(?:<p>)?MATH-BLOCK-(\d+)(?:</p>)?`)
+	mathInlineRe = regexp.MustCompile(`MATH-INLINE-(\d+)`)
+	// Префикс цитаты, чтобы $$ работали и внутри выносок
+	quotePrefixRe = regexp.MustCompile(`^\s*(?:>\s?)*`)
+)
+
+type formula struct {
+	tex     string
+	display bool
+}
+
+func mathPlugin() Plugin {
+	return Plugin{
+		Name: "math",
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			md, formulas := extractMath(md)
+			ctx.Set("math", formulas)
+			return md, nil
+		},
+		PostRender: func(ctx *Context, html []byte) ([]byte, error) {
+			formulas, _ := ctx.Get("math").([]formula)
+			return renderMath(html, formulas), nil
+		},
+	}
+}
+
+// extractMath заменяет $$...$$ и $...$ вне кода заглушками
+func extractMath(md []byte) ([]byte, []formula) {
+	lines := strings.Split(string(md), "\n")
+	var out []string
+	var formulas []formula
+
+	add := func(f formula, placeholder string) string {
+		formulas = append(formulas, f)
+		return fmt.Sprintf(placeholder, len(formulas)-1)
+	}
+
+	inFence := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			out = append(out, line)
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+
+		prefix := quotePrefixRe.FindString(line)
+		if rest := strings.TrimSpace(line[len(prefix):]); strings.HasPrefix(rest, "$$") {
+			if tex, end, ok := mathBlock(lines, i, prefix); ok {
+				quote := strings.TrimRight(prefix, " \t")
+				out = append(out, quote, prefix+add(formula{tex: tex, display: true}, mathBlockPlaceholder), quote)
+				i = end
+				continue
+			}
+		}
+
+		out = append(out, replaceOutsideCode(line, func(text string) string {
+			return extractInlineMath(text, func(tex string, display bool) string {
+				return add(formula{tex: tex, display: display}, mathInlinePlaceholder)
+			})
+		}))
+	}
+
+	if len(formulas) == 0 {
+		return md, nil
+	}
+	return []byte(strings.Join(out, "\n")), formulas
+}
+
+// mathBlock собирает формулу $$...$$, начинающуюся на строке start; end — строка с закрывающими $$
+func mathBlock(lines []string, start int, prefix string) (tex string, end int, ok bool) {
+	first := strings.TrimSpace(lines[start][len(prefix):])[2:]
+	if strings.HasSuffix(first, "$$") {
+		return strings.TrimSpace(strings.TrimSuffix(first, "$$")), start, true
+	}
+
+	body := []string{first}
+	for end = start + 1; end < len(lines); end++ {
+		line := strings.TrimPrefix(lines[end], strings.TrimRight(prefix, " \t"))
+		line = strings.TrimPrefix(line, " ")
+		if trimmed := strings.TrimSpace(line); strings.HasSuffix(trimmed, "$$") {
+			body = append(body, strings.TrimSuffix(trimmed, "$$"))
+			return strings.TrimSpace(strings.Join(body, "\n")), end, true
+		}
+		body = append(body, line)
+	}
+	return "", start, false
+}
+
+// extractInlineMath находит $...$ по правилам Pandoc: после открывающего $ нет пробела,
+// перед закрывающим нет пробела, а сразу за ним не идёт цифра, так что «$5 и $10» не формула
+func extractInlineMath(text string, add func(tex string, display bool) string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == '\\' && i+1 < len(text) {
+			b.WriteString(text[i : i+2])
+			i++
+			continue
+		}
+		if c != '$' {
+			b.WriteByte(c)
+			continue
+		}
+
+		if strings.HasPrefix(text[i:], "$$") {
+			if end := strings.Index(text[i+2:], "$$"); end > 0 {
+				b.WriteString(add(strings.TrimSpace(text[i+2:i+2+end]), true))
+				i += end + 3
+				continue
+			}
+			b.WriteString("$$")
+			i++
+			continue
+		}
+
+		if end := closingDollar(text, i); end > 0 {
+			b.WriteString(add(text[i+1:end], false))
+			i = end
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func closingDollar(text string, open int) int {
+	if open+1 >= len(text) || text[open+1] == ' ' || text[open+1] == '\t' {
+		return -1
+	}
+	for j := open + 2; j < len(text); j++ {
+		switch {
+		case text[j] == '\\':
+			j++
+		case text[j] == '$':
+			if text[j-1] == ' ' || text[j-1] == '\t' {
+				continue
+			}
+			if j+1 < len(text) && text[j+1] >= '0' && text[j+1] <= '9' {
+				continue
+			}
+			return j
+		}
+	}
+	return -1
+}
+
+// renderMath подставляет MathML на место заглушек; ошибочная формула выводится исходным текстом
+func renderMath(htmlContent []byte, formulas []formula) []byte {
+	if len(formulas) == 0 {
+		return htmlContent
+	}
+	replace := func(re *regexp.Regexp) func([]byte) []byte {
+		return func(match []byte) []byte {
+			i, err := strconv.Atoi(string(re.FindSubmatch(match)[1]))
+			if err != nil || i >= len(formulas) {
+				return match
+			}
+			f := formulas[i]
+			out, err := mathml.Render(f.tex, f.display)
+			if err != nil {
+				log.Warnf("Не удалось отрисовать формулу: %v", err)
+				return []byte(fmt.Sprintf(`<code class="math-error" title="%s">%s</code>`,
+					html.EscapeString(err.Error()), html.EscapeString(f.tex)))
+			}
+			return []byte(out)
+		}
+	}
+	htmlContent = mathBlockRe.ReplaceAllFunc(htmlContent, replace(mathBlockRe))
+	return mathInlineRe.ReplaceAllFunc(htmlContent, replace(mathInlineRe))
+}
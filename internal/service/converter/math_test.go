@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractMath(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       string
+		want     string
+		formulas []formula
+	}{
+		{
+			name:     "Строчная формула",
+			md:       "Площадь $S = \\pi r^2$ круга",
+			want:     "Площадь MATH-INLINE-0 круга",
+			formulas: []formula{{tex: "S = \\pi r^2"}},
+		},
+		{
+			name: "Цены не формулы",
+			md:   "От $5 до $10",
+			want: "От $5 до $10",
+		},
+		{
+			name: "Экранированный доллар",
+			md:   "Цена \\$x$ и $ y$",
+			want: "Цена \\$x$ и $ y$",
+		},
+		{
+			name:     "Выносная формула в одну строку",
+			md:       "$$a_1 + a_2$$",
+			want:     "\nMATH-BLOCK-0\n",
+			formulas: []formula{{tex: "a_1 + a_2", display: true}},
+		},
+		{
+			name:     "Выносная формула на несколько строк",
+			md:       "Текст\n$$\n\\frac{a}{b}\n$$\nдальше",
+			want:     "Текст\n\nMATH-BLOCK-0\n\nдальше",
+			formulas: []formula{{tex: "\\frac{a}{b}", display: true}},
+		},
+		{
+			name:     "Формула внутри выноски",
+			md:       "> [!note]\n> $$\n> x_1\n> $$",
+			want:     "> [!note]\n>\n> MATH-BLOCK-0\n>",
+			formulas: []formula{{tex: "x_1", display: true}},
+		},
+		{
+			name: "Код не трогается",
+			md:   "`$x$`\n```\n$$y$$\n```",
+			want: "`$x$`\n```\n$$y$$\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, formulas := extractMath([]byte(tt.md))
+			require.Equal(t, tt.want, string(got))
+			require.Equal(t, tt.formulas, formulas)
+		})
+	}
+}
+
+func TestMathPlugin(t *testing.T) {
+	sut := NewConverter()
+
+	got, err := sut.renderMarkdown(sut.newContext("", nil),
+		[]byte("Если $a_i * b_i$ и $\\foo$\n\n> [!tip]\n> $$x^2$$"))
+
+	require.NoError(t, err)
+	require.Contains(t, string(got), "<msub><mi>a</mi><mi>i</mi></msub><mo>∗</mo>")
+	require.NotContains(t, string(got), "<em>")
+	require.Contains(t, string(got), `<code class="math-error" title="ошибка в формуле &#34;\\foo&#34;: неизвестная команда \foo">\foo</code>`)
+	require.Contains(t, string(got), `<div class="callout-content">`+"\n"+`<math xmlns="http://www.w3.org/1998/Math/MathML" display="block">`)
+}
@@ -0,0 +1,118 @@
+package converter
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	log "github.com/sirupsen/logrus"
+	"github.com/yuin/goldmark/ast"
+)
+
+// mdLinkPlugin переводит относительные ссылки [текст](../projects/alpha.md) на страницы заметок
+// и предупреждает о ссылках и картинках, которых нет в исходной директории. Внешние ссылки не меняются
+func mdLinkPlugin() Plugin {
+	return Plugin{
+		Name:         "mdlinks",
+		TransformAST: rewriteMarkdownLinks,
+	}
+}
+
+func rewriteMarkdownLinks(ctx *Context, doc ast.Node, source []byte) {
+	if ctx.Note == nil {
+		return
+	}
+	var v *vault.Vault
+	if ctx.SrcDir != "" {
+		var err error
+		if v, err = ctx.Vault(); err != nil {
+			log.Debugf("Не удалось загрузить заметки для проверки ссылок: %v", err)
+		}
+	}
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Link:
+			if href, ok := markdownLinkHref(ctx, v, string(n.Destination)); ok {
+				n.Destination = []byte(href)
+			}
+		case *ast.Image:
+			markdownLinkHref(ctx, v, string(n.Destination))
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// markdownLinkHref возвращает новый адрес для ссылки на заметку; false — адрес остаётся прежним
+func markdownLinkHref(ctx *Context, v *vault.Vault, dest string) (string, bool) {
+	target, anchor, _ := strings.Cut(dest, "#")
+	link := vault.Link{Target: target}
+	if target == "" || link.IsExternal() {
+		return "", false
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	link.Target = target
+
+	if strings.ToLower(path.Ext(target)) != ".md" {
+		// Вложения не копируются, поэтому ссылка остаётся как есть, но отсутствующий файл стоит заметить
+		if ctx.SrcDir != "" {
+			if _, err := os.Stat(sourcePath(ctx, target)); os.IsNotExist(err) {
+				warnMissingLink(ctx, target)
+			}
+		}
+		return "", false
+	}
+
+	var relPath string
+	switch {
+	case v != nil:
+		note := v.Resolve(ctx.Note, link)
+		if note == nil {
+			warnMissingLink(ctx, target)
+			return "", false
+		}
+		relPath = note.RelPath
+	case strings.HasPrefix(target, "/"):
+		relPath = path.Clean(strings.TrimPrefix(target, "/"))
+	default:
+		relPath = path.Join(path.Dir(filepath.ToSlash(ctx.Note.RelPath)), target)
+	}
+
+	href := escapePath(ctx.PageURL(relPath))
+	if unescaped, err := url.PathUnescape(anchor); err == nil && unescaped != "" {
+		// Так же, как вики-ссылки: #^id ведёт к блоку, #Раздел — к заголовку
+		if strings.HasPrefix(unescaped, "^") {
+			href += "#" + url.PathEscape(unescaped[1:])
+		} else {
+			href += "#" + url.PathEscape(vault.Slug(unescaped))
+		}
+	}
+	return href, true
+}
+
+// sourcePath — путь к файлу ссылки в исходной директории: от корня хранилища для /path, иначе от папки заметки
+func sourcePath(ctx *Context, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return filepath.Join(ctx.SrcDir, filepath.FromSlash(target))
+	}
+	return filepath.Join(ctx.SrcDir, filepath.Dir(ctx.Note.RelPath), filepath.FromSlash(target))
+}
+
+// warnMissingLink пишет предупреждение с номером строки ссылки, если разбор заметки её нашёл
+func warnMissingLink(ctx *Context, target string) {
+	for _, l := range ctx.Note.Links {
+		if !l.Wiki && l.Target == target {
+			log.Warnf("Ссылка на несуществующий файл %s в %s:%d", target, ctx.Note.RelPath, l.Line)
+			return
+		}
+	}
+	log.Warnf("Ссылка на несуществующий файл %s в %s", target, ctx.Note.RelPath)
+}
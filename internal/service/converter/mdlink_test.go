@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownLinks(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(srcDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644))
+	}
+	write("projects/alpha.md", "---\nslug: alpha-project\n---\n# Alpha\n")
+	write("files/plan.pdf", "pdf")
+	write("daily/day.md", "[Проект](../projects/alpha.md#Итоги%20года) [корень](/projects/alpha.md)\n"+
+		"[сайт](https://example.com/a.md) [план](../files/plan.pdf) [почта](mailto:a@b.c)\n"+
+		"[нет](../projects/beta.md)\n![схема](missing.png)\n`[код](../projects/alpha.md)`\n")
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	require.NoError(t, NewConverter().ConvertDirectory(srcDir, destDir))
+
+	content, err := os.ReadFile(filepath.Join(destDir, "day.html"))
+	require.NoError(t, err)
+	html := string(content)
+	require.Contains(t, html, `<a href="alpha-project.html#%D0%B8%D1%82%D0%BE%D0%B3%D0%B8-%D0%B3%D0%BE%D0%B4%D0%B0">Проект</a>`)
+	require.Contains(t, html, `<a href="alpha-project.html">корень</a>`)
+	require.Contains(t, html, `<a href="https://example.com/a.md">сайт</a>`)
+	require.Contains(t, html, `<a href="../files/plan.pdf">план</a>`)
+	require.Contains(t, html, `<a href="mailto:a@b.c">почта</a>`)
+	require.Contains(t, html, `<a href="../projects/beta.md">нет</a>`)
+	require.Contains(t, html, `<code>[код](../projects/alpha.md)</code>`)
+
+	require.Contains(t, logs.String(), "Ссылка на несуществующий файл ../projects/beta.md в daily/day.md:3")
+	require.Contains(t, logs.String(), "Ссылка на несуществующий файл missing.png в daily/day.md:4")
+	require.NotContains(t, logs.String(), "plan.pdf")
+}
+
+func TestMarkdownLinks_WithoutVault(t *testing.T) {
+	note := &vault.Note{RelPath: "daily/day.md", Body: []byte("[a](../projects/Alpha.md) [b](#Итоги)")}
+
+	out, err := NewConverter(WithURLs(URLOptions{Style: URLTranslit})).RenderNote(note, "")
+
+	require.NoError(t, err)
+	require.Contains(t, string(out), `<a href="alpha.html">a</a>`)
+	require.Contains(t, string(out), `<a href="#%D0%98%D1%82%D0%BE%D0%B3%D0%B8">b</a>`)
+}
@@ -0,0 +1,163 @@
+package converter
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MermaidScript — диаграммы рисует в браузере локальная копия mermaid.js
+	MermaidScript = "script"
+	// MermaidSVG — диаграммы заранее отрисовывает mermaid-cli (mmdc)
+	MermaidSVG = "svg"
+
+	// Имя, под которым скрипт копируется в целевую директорию и хранится во встроенных файлах
+	mermaidScriptFile  = "mermaid.min.js"
+	mermaidPlaceholder = "MERMAID-BLOCK-%d"
+)
+
+//go:generate curl -fsSL -o assets/mermaid.min.js https://cdn.jsdelivr.net/npm/mermaid@10.9.1/dist/mermaid.min.js
+
+// Встроенная копия mermaid.min.js для режима script без своего скрипта; подменяется в тестах
+var (
+	//go:embed assets
+	embeddedAssets embed.FS
+	bundledAssets  fs.FS = embeddedAssets
+)
+
+var (
+	mermaidPlaceholderRe = regexp.MustCompile(`(?:<p>)?MERMAID-BLOCK-(\d+)(?:</p>)?`)
+	xmlPrologRe          = regexp.MustCompile(`^\s*<\?xml[^>]*\?>\s*`)
+)
+
+// MermaidOptions — настройки вывода диаграмм Mermaid
+type MermaidOptions struct {
+	Mode string
+	// Путь к своему mermaid.min.js для режима script; пусто — встроенная копия
+	Script string
+	// Команда mermaid-cli для режима svg
+	CLI string
+}
+
+// WithMermaid задаёт способ вывода диаграмм Mermaid
+func WithMermaid(opts MermaidOptions) Option {
+	return func(c *Converter) {
+		c.mermaid = opts
+	}
+}
+
+func mermaidPlugin(opts MermaidOptions) Plugin {
+	if opts.Mode != MermaidScript && opts.Mode != MermaidSVG {
+		if opts.Mode != "" {
+			log.Warnf("Неизвестный режим Mermaid %s, используется %s", opts.Mode, MermaidScript)
+		}
+		opts.Mode = MermaidScript
+	}
+
+	// Без своего скрипта используется встроенный
+	var bundled []byte
+	if opts.Script == "" {
+		bundled, _ = fs.ReadFile(bundledAssets, path.Join("assets", mermaidScriptFile))
+	}
+	hasScript := opts.Script != "" || len(bundled) > 0
+
+	// Без скрипта браузер покажет диаграммы исходным текстом; об этом достаточно сообщить один раз
+	var missingScript sync.Once
+
+	return Plugin{
+		Name: "mermaid",
+		BeforeRun: func(run *Run) ([]Page, error) {
+			if len(bundled) > 0 {
+				return []Page{{Path: mermaidScriptFile, Content: bundled}}, nil
+			}
+			if opts.Script == "" {
+				return nil, nil
+			}
+			content, err := os.ReadFile(opts.Script)
+			if err != nil {
+				log.Errorf("Не удалось прочитать скрипт Mermaid %s: %v", opts.Script, err)
+				return nil, fmt.Errorf("не удалось прочитать скрипт Mermaid: %v", err)
+			}
+			return []Page{{Path: mermaidScriptFile, Content: content}}, nil
+		},
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			md, diagrams := extractFences(md, mermaidPlaceholder, func(lang string) bool { return lang == "mermaid" })
+			ctx.Set("mermaid", diagrams)
+			return md, nil
+		},
+		PostRender: func(ctx *Context, htmlContent []byte) ([]byte, error) {
+			diagrams, _ := ctx.Get("mermaid").([]codeBlock)
+			if len(diagrams) == 0 {
+				return htmlContent, nil
+			}
+			return mermaidPlaceholderRe.ReplaceAllFunc(htmlContent, func(match []byte) []byte {
+				i, err := strconv.Atoi(string(mermaidPlaceholderRe.FindSubmatch(match)[1]))
+				if err != nil || i >= len(diagrams) {
+					return match
+				}
+				source := diagrams[i].code
+
+				if opts.Mode == MermaidSVG {
+					svg, err := renderMermaidSVG(opts.CLI, source)
+					if err == nil {
+						return []byte("<figure class=\"mermaid-diagram\">" + svg + "</figure>\n")
+					}
+					log.Warnf("Не удалось отрисовать диаграмму Mermaid, выводится исходный текст: %v", err)
+				}
+				if !hasScript {
+					missingScript.Do(func() {
+						log.Errorf("Нет встроенного mermaid.min.js и не задан mermaid.script: диаграммы Mermaid выводятся исходным текстом")
+					})
+				} else {
+					ctx.AddHead(fmt.Sprintf("<script src=\"%s\"></script>\n<script>mermaid.initialize({startOnLoad: true});</script>\n",
+						escapePath(ctx.AssetURL(mermaidScriptFile))))
+				}
+				return []byte("<div class=\"mermaid\">" + html.EscapeString(source) + "</div>\n")
+			}), nil
+		},
+	}
+}
+
+// renderMermaidSVG отрисовывает диаграмму через mermaid-cli во временной директории
+func renderMermaidSVG(cli, source string) (string, error) {
+	if cli == "" {
+		cli = "mmdc"
+	}
+	dir, err := os.MkdirTemp("", "mermaid")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "diagram.mmd")
+	output := filepath.Join(dir, "diagram.svg")
+	if err := os.WriteFile(input, []byte(source), 0644); err != nil {
+		return "", err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(cli, "-i", input, "-o", output, "-b", "transparent")
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %v: %s", cli, err, strings.TrimSpace(stderr.String()))
+	}
+
+	svg, err := os.ReadFile(output)
+	if err != nil {
+		return "", err
+	}
+	return xmlPrologRe.ReplaceAllString(string(svg), ""), nil
+}
@@ -0,0 +1,98 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+const mermaidNote = "# Схема\n\n```mermaid\ngraph TD\n  A --> B\n```\n\n```go\nx := 1\n```\n"
+
+func TestMermaidPlugin_Script(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	script := filepath.Join(srcDir, "vendor.js")
+	require.NoError(t, os.WriteFile(script, []byte("window.mermaid = {};"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "note.md"), []byte(mermaidNote), 0644))
+
+	sut := NewConverter(WithMermaid(MermaidOptions{Mode: MermaidScript, Script: script}))
+	require.NoError(t, sut.ConvertDirectory(srcDir, destDir))
+
+	copied, err := os.ReadFile(filepath.Join(destDir, "mermaid.min.js"))
+	require.NoError(t, err)
+	require.Equal(t, "window.mermaid = {};", string(copied))
+
+	content, err := os.ReadFile(filepath.Join(destDir, "note.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "<div class=\"mermaid\">graph TD\n  A --&gt; B</div>")
+	require.Contains(t, string(content), `<script src="mermaid.min.js"></script>`)
+	require.Contains(t, string(content), `<div class="highlight" data-lang="go">`)
+}
+
+func TestMermaidPlugin_BundledScript(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	saved := bundledAssets
+	bundledAssets = fstest.MapFS{"assets/mermaid.min.js": {Data: []byte("window.mermaid = 'bundled';")}}
+	defer func() { bundledAssets = saved }()
+
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "note.md"), []byte(mermaidNote), 0644))
+
+	// Настройки по умолчанию: режим script без своего скрипта
+	sut := NewConverter(WithMermaid(MermaidOptions{Mode: MermaidScript}))
+	require.NoError(t, sut.ConvertDirectory(srcDir, destDir))
+
+	copied, err := os.ReadFile(filepath.Join(destDir, "mermaid.min.js"))
+	require.NoError(t, err)
+	require.Equal(t, "window.mermaid = 'bundled';", string(copied))
+
+	content, err := os.ReadFile(filepath.Join(destDir, "note.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), `<script src="mermaid.min.js"></script>`)
+}
+
+func TestMermaidPlugin_SVG(t *testing.T) {
+	dir, err := os.MkdirTemp("", "mermaid_cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Заглушка mermaid-cli: пишет SVG в файл после -o
+	cli := filepath.Join(dir, "mmdc")
+	require.NoError(t, os.WriteFile(cli, []byte("#!/bin/sh\nprintf '<?xml version=\"1.0\"?>\\n<svg>ok</svg>' > \"$4\"\n"), 0755))
+
+	sut := NewConverter(WithMermaid(MermaidOptions{Mode: MermaidSVG, CLI: cli}))
+	ctx := sut.newContext("", nil)
+	got, err := sut.renderMarkdown(ctx, []byte(mermaidNote))
+
+	require.NoError(t, err)
+	require.Contains(t, string(got), "<figure class=\"mermaid-diagram\"><svg>ok</svg></figure>")
+	for _, h := range ctx.head {
+		require.NotContains(t, h, "mermaid.min.js")
+	}
+}
+
+func TestMermaidPlugin_SVGFallback(t *testing.T) {
+	sut := NewConverter(WithMermaid(MermaidOptions{Mode: MermaidSVG, CLI: "/nonexistent/mmdc"}))
+
+	got, err := sut.renderMarkdown(sut.newContext("", nil), []byte(mermaidNote))
+
+	require.NoError(t, err)
+	require.Contains(t, string(got), `<div class="mermaid">graph TD`)
+}
@@ -0,0 +1,223 @@
+package converter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/page"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/yuin/goldmark/ast"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Plugin добавляет в конвертацию собственный синтаксис и страницы. Все хуки необязательны.
+// PreParse вызываются в порядке регистрации, PostRender — в обратном: плагин, который
+// первым заменил фрагмент заглушкой, последним возвращает его на место. Плагины из
+// WithPlugins регистрируются раньше встроенных, поэтому видят исходный Markdown и итоговый HTML
+type Plugin struct {
+	Name string
+
+	// PreParse меняет исходный Markdown до рендеринга
+	PreParse func(ctx *Context, md []byte) ([]byte, error)
+	// TransformAST меняет дерево документа goldmark; с рендерерами без AST не вызывается
+	TransformAST func(ctx *Context, doc ast.Node, source []byte)
+	// PostRender меняет готовый HTML тела заметки
+	PostRender func(ctx *Context, html []byte) ([]byte, error)
+
+	// BeforeRun и AfterRun вызываются до и после конвертации всех заметок в ConvertDirectory
+	// и могут вернуть дополнительные страницы
+	BeforeRun func(run *Run) ([]Page, error)
+	AfterRun  func(run *Run) ([]Page, error)
+}
+
+// Page — дополнительный файл, который плагин просит записать в целевую директорию
+type Page struct {
+	// Путь относительно целевой директории
+	Path    string
+	Content []byte
+}
+
+// Context — состояние отрисовки одной заметки, общее для всех хуков
+type Context struct {
+	SrcDir string
+	// Заметка, которую отрисовывают; nil для вложенных фрагментов без файла
+	Note *vault.Note
+	Now  time.Time
+	// Dynamic помечает результат зависящим от других заметок: такой файл пересобирается при каждом запуске
+	Dynamic bool
+	// Оглавление, которое страница заметки выводит перед текстом
+	TOC []page.TOCItem
+
+	conv   *Converter
+	values map[string]any
+	head   []string
+}
+
+// Vault возвращает всё хранилище; загружается один раз за запуск
+func (ctx *Context) Vault() (*vault.Vault, error) {
+	return ctx.conv.loadVault(ctx.SrcDir)
+}
+
+// Render отрисовывает фрагмент Markdown рендерером конвертера с преобразованиями AST,
+// но без PreParse и PostRender: они применяются ко всему тексту заметки
+func (ctx *Context) Render(md []byte) ([]byte, error) {
+	return ctx.conv.render(ctx, md)
+}
+
+// PageURL возвращает ссылку со страницы текущей заметки на страницу заметки relPath
+func (ctx *Context) PageURL(relPath string) string {
+	return relURL(ctx.page(), ctx.conv.pageURL(ctx.SrcDir, relPath))
+}
+
+// AssetURL возвращает ссылку со страницы текущей заметки на файл name в корне целевой директории
+func (ctx *Context) AssetURL(name string) string {
+	return strings.Repeat("../", strings.Count(ctx.page(), "/")) + name
+}
+
+// page — путь страницы текущей заметки относительно целевой директории
+func (ctx *Context) page() string {
+	if ctx.Note == nil {
+		return ""
+	}
+	return ctx.conv.pageURL(ctx.SrcDir, ctx.Note.RelPath)
+}
+
+// AddHead добавляет HTML в <head> страницы заметки; повторы пропускаются
+func (ctx *Context) AddHead(html string) {
+	for _, h := range ctx.head {
+		if h == html {
+			return
+		}
+	}
+	ctx.head = append(ctx.head, html)
+}
+
+// Set и Get хранят данные плагина между хуками одной заметки
+func (ctx *Context) Set(key string, value any) {
+	if ctx.values == nil {
+		ctx.values = make(map[string]any)
+	}
+	ctx.values[key] = value
+}
+
+func (ctx *Context) Get(key string) any {
+	return ctx.values[key]
+}
+
+// Run описывает один запуск ConvertDirectory
+type Run struct {
+	SrcDir  string
+	DestDir string
+	Now     time.Time
+
+	conv *Converter
+}
+
+func (run *Run) Vault() (*vault.Vault, error) {
+	return run.conv.loadVault(run.SrcDir)
+}
+
+// PageURL возвращает путь страницы заметки relPath относительно целевой директории
+func (run *Run) PageURL(relPath string) string {
+	return run.conv.pageURL(run.SrcDir, relPath)
+}
+
+// RenderNote возвращает HTML тела заметки, уже отрисованный при конвертации страницы.
+// Повторная отрисовка нужна только заметкам, которые не конвертировались в этом запуске
+func (run *Run) RenderNote(note *vault.Note) ([]byte, error) {
+	run.conv.mu.Lock()
+	htmlContent, ok := run.conv.rendered[note.RelPath]
+	run.conv.mu.Unlock()
+	if ok {
+		return htmlContent, nil
+	}
+	return run.conv.RenderNote(note, run.SrcDir)
+}
+
+// WithPlugins регистрирует плагины раньше встроенных
+func WithPlugins(plugins ...Plugin) Option {
+	return func(c *Converter) {
+		c.plugins = append(c.plugins, plugins...)
+	}
+}
+
+func (c *Converter) newContext(srcDir string, note *vault.Note) *Context {
+	return &Context{SrcDir: srcDir, Note: note, Now: c.currentTime(), conv: c}
+}
+
+// renderMarkdown проводит Markdown через хуки плагинов и рендерер
+func (c *Converter) renderMarkdown(ctx *Context, md []byte) ([]byte, error) {
+	var err error
+	for _, p := range c.plugins {
+		if p.PreParse == nil {
+			continue
+		}
+		if md, err = p.PreParse(ctx, md); err != nil {
+			return nil, fmt.Errorf("плагин %s: %v", p.Name, err)
+		}
+	}
+
+	htmlContent, err := c.render(ctx, md)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(c.plugins) - 1; i >= 0; i-- {
+		p := c.plugins[i]
+		if p.PostRender == nil {
+			continue
+		}
+		if htmlContent, err = p.PostRender(ctx, htmlContent); err != nil {
+			return nil, fmt.Errorf("плагин %s: %v", p.Name, err)
+		}
+	}
+	return htmlContent, nil
+}
+
+func (c *Converter) render(ctx *Context, md []byte) ([]byte, error) {
+	r, ok := c.renderer.(ASTRenderer)
+	if !ok {
+		return c.renderer.Render(md)
+	}
+	return r.RenderAST(md, func(doc ast.Node, source []byte) {
+		for _, p := range c.plugins {
+			if p.TransformAST != nil {
+				p.TransformAST(ctx, doc, source)
+			}
+		}
+	})
+}
+
+// runHooks вызывает BeforeRun или AfterRun всех плагинов и записывает возвращённые страницы
+func (c *Converter) runHooks(run *Run, after bool) error {
+	for _, p := range c.plugins {
+		hook := p.BeforeRun
+		if after {
+			hook = p.AfterRun
+		}
+		if hook == nil {
+			continue
+		}
+
+		pages, err := hook(run)
+		if err != nil {
+			log.Errorf("Ошибка в плагине %s: %v", p.Name, err)
+			return fmt.Errorf("плагин %s: %v", p.Name, err)
+		}
+		for _, pg := range pages {
+			path := filepath.Join(run.DestDir, pg.Path)
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				log.Errorf("Не удалось создать директорию для %s: %v", path, err)
+				return fmt.Errorf("не удалось создать директорию для %s: %v", path, err)
+			}
+			if err := writePage(path, pg.Content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package converter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark/ast"
+)
+
+func TestConverter_Plugins(t *testing.T) {
+	var calls []string
+	headings := 0
+
+	plugin := Plugin{
+		Name: "test",
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			calls = append(calls, "pre:"+ctx.Note.Name)
+			return bytes.ReplaceAll(md, []byte("==важно=="), []byte("<mark>важно</mark>")), nil
+		},
+		TransformAST: func(ctx *Context, doc ast.Node, source []byte) {
+			_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+				if h, ok := n.(*ast.Heading); ok && entering {
+					h.Level = 2
+					headings++
+				}
+				return ast.WalkContinue, nil
+			})
+		},
+		PostRender: func(ctx *Context, html []byte) ([]byte, error) {
+			return append(html, []byte("<footer>подвал</footer>")...), nil
+		},
+		BeforeRun: func(run *Run) ([]Page, error) {
+			calls = append(calls, "before")
+			return nil, nil
+		},
+		AfterRun: func(run *Run) ([]Page, error) {
+			v, err := run.Vault()
+			if err != nil {
+				return nil, err
+			}
+			calls = append(calls, "after")
+			return []Page{{Path: "extra/count.txt", Content: []byte{byte('0' + len(v.Notes))}}}, nil
+		},
+	}
+	sut := NewConverter(WithPlugins(plugin))
+
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "note.md"), []byte("# Заметка\n\nЭто ==важно=="), 0644))
+
+	require.NoError(t, sut.ConvertDirectory(srcDir, destDir))
+
+	require.Equal(t, []string{"before", "pre:note", "after"}, calls)
+	require.Equal(t, 1, headings)
+
+	content, err := os.ReadFile(filepath.Join(destDir, "note.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), `<h2 id="заметка">Заметка <a class="heading-anchor"`)
+	require.Contains(t, string(content), "<mark>важно</mark>")
+	require.Contains(t, string(content), "<footer>подвал</footer>")
+
+	extra, err := os.ReadFile(filepath.Join(destDir, "extra", "count.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "1", string(extra))
+}
+
+func TestContext_Values(t *testing.T) {
+	ctx := NewConverter().newContext("", nil)
+
+	require.Nil(t, ctx.Get("key"))
+	ctx.Set("key", 42)
+	require.Equal(t, 42, ctx.Get("key"))
+}
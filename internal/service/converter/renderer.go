@@ -0,0 +1,105 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/russross/blackfriday/v2"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	RendererGoldmark    = "goldmark"
+	RendererBlackfriday = "blackfriday"
+)
+
+// Renderer превращает Markdown в HTML
+type Renderer interface {
+	Render(src []byte) ([]byte, error)
+}
+
+// ASTRenderer — рендерер, который даёт плагинам изменить дерево документа перед отрисовкой
+type ASTRenderer interface {
+	Renderer
+	RenderAST(src []byte, transform func(doc ast.Node, source []byte)) ([]byte, error)
+}
+
+// NewRenderer возвращает реализацию по имени из конфигурации; пустое имя — goldmark
+func NewRenderer(name string) (Renderer, error) {
+	switch name {
+	case "", RendererGoldmark:
+		return NewGoldmarkRenderer(), nil
+	case RendererBlackfriday:
+		return BlackfridayRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("неизвестный рендерер Markdown: %s", name)
+	}
+}
+
+type GoldmarkRenderer struct {
+	md goldmark.Markdown
+}
+
+// NewGoldmarkRenderer включает таблицы, автоссылки и зачёркивание из GFM, сноски,
+// списки определений и id заголовков (в том числе из кириллицы). Списки задач GFM не включены: чекбоксы,
+// в том числе [/] и [-], отрисовывает renderTaskCheckboxes
+func NewGoldmarkRenderer() *GoldmarkRenderer {
+	return &GoldmarkRenderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(
+				extension.Table,
+				extension.Strikethrough,
+				extension.Linkify,
+				extension.Footnote,
+				extension.DefinitionList,
+			),
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+			// HTML из заметок и разметка полей в тексте выводятся как есть, как в blackfriday
+			goldmark.WithRendererOptions(html.WithUnsafe()),
+		),
+	}
+}
+
+func (r *GoldmarkRenderer) Render(src []byte) ([]byte, error) {
+	return r.RenderAST(src, nil)
+}
+
+func (r *GoldmarkRenderer) RenderAST(src []byte, transform func(doc ast.Node, source []byte)) ([]byte, error) {
+	pc := parser.NewContext(parser.WithIDs(&headingIDs{slugger: vault.NewSlugger()}))
+	doc := r.md.Parser().Parse(text.NewReader(src), parser.WithContext(pc))
+	if transform != nil {
+		transform(doc, src)
+	}
+
+	var buf bytes.Buffer
+	if err := r.md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, fmt.Errorf("не удалось отрисовать Markdown: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// headingIDs заменяет id заголовков goldmark, из которых пропадают буквы не из латиницы,
+// на слаги vault.Slug, уникальные в пределах документа
+type headingIDs struct {
+	slugger *vault.Slugger
+}
+
+func (ids *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	return []byte(ids.slugger.Unique(string(value)))
+}
+
+// Put нужен для явных id из атрибутов, которые не включены
+func (ids *headingIDs) Put(value []byte) {}
+
+// BlackfridayRenderer — прежний рендерер с настройками blackfriday по умолчанию
+type BlackfridayRenderer struct{}
+
+func (BlackfridayRenderer) Render(src []byte) ([]byte, error) {
+	return blackfriday.Run(src), nil
+}
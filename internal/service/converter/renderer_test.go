@@ -0,0 +1,83 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRenderer(t *testing.T) {
+	r, err := NewRenderer("")
+	require.NoError(t, err)
+	require.IsType(t, &GoldmarkRenderer{}, r)
+
+	r, err = NewRenderer(RendererBlackfriday)
+	require.NoError(t, err)
+	require.IsType(t, BlackfridayRenderer{}, r)
+
+	_, err = NewRenderer("pandoc")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "неизвестный рендерер Markdown: pandoc")
+}
+
+func TestGoldmarkRenderer(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{
+			name: "Таблица",
+			md:   "| a | b |\n|---|---|\n| 1 | 2 |",
+			want: "<td>1</td>",
+		},
+		{
+			name: "Зачёркивание",
+			md:   "~~старое~~",
+			want: "<del>старое</del>",
+		},
+		{
+			name: "Сноска",
+			md:   "Текст[^1]\n\n[^1]: Пояснение",
+			want: `<div class="footnotes" role="doc-endnotes">`,
+		},
+		{
+			name: "Список определений",
+			md:   "Термин\n: Определение",
+			want: "<dl>\n<dt>Термин</dt>\n<dd>Определение</dd>\n</dl>",
+		},
+		{
+			name: "id заголовка",
+			md:   "## Planning notes",
+			want: `<h2 id="planning-notes">Planning notes</h2>`,
+		},
+		{
+			name: "HTML выводится как есть",
+			md:   `Сон <span class="inline-field">7h</span>`,
+			want: `<span class="inline-field">7h</span>`,
+		},
+		{
+			name: "Чекбоксы остаются текстом для renderTaskCheckboxes",
+			md:   "- [/] в работе",
+			want: "<li>[/] в работе</li>",
+		},
+	}
+
+	r := NewGoldmarkRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Render([]byte(tt.md))
+			require.NoError(t, err)
+			require.Contains(t, string(got), tt.want)
+		})
+	}
+}
+
+func TestConverter_WithRenderer(t *testing.T) {
+	sut := NewConverter(WithRenderer(BlackfridayRenderer{}))
+
+	got, err := sut.renderMarkdown(sut.newContext("", nil), []byte("## Заголовок"))
+
+	require.NoError(t, err)
+	require.Equal(t, "<h2>Заголовок</h2>\n", string(got))
+}
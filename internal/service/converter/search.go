@@ -0,0 +1,57 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SearchFile — поисковый индекс сайта для поиска на стороне браузера
+const SearchFile = "search.json"
+
+type searchEntry struct {
+	Title   string   `json:"title"`
+	URL     string   `json:"url"`
+	Aliases []string `json:"aliases,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Date    string   `json:"date,omitempty"`
+}
+
+// searchPlugin пишет search.json с заголовками, псевдонимами, тегами и адресами опубликованных заметок,
+// чтобы заметку можно было найти и по старому имени
+func searchPlugin() Plugin {
+	return Plugin{
+		Name: "search",
+		AfterRun: func(run *Run) ([]Page, error) {
+			v, err := run.Vault()
+			if err != nil {
+				log.Errorf("Не удалось загрузить заметки: %v", err)
+				return nil, fmt.Errorf("не удалось загрузить заметки: %v", err)
+			}
+
+			entries := make([]searchEntry, 0, len(v.Notes))
+			for _, note := range v.Notes {
+				if !published(note) {
+					continue
+				}
+				entry := searchEntry{Title: note.Title(), URL: escapePath(run.PageURL(note.RelPath)), Tags: note.Tags}
+				if note.FrontMatter != nil {
+					entry.Aliases = note.FrontMatter.Aliases
+				}
+				if date, ok := note.Date(); ok {
+					entry.Date = date.Format(vault.DateLayout)
+				}
+				entries = append(entries, entry)
+			}
+
+			content, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("не удалось записать поисковый индекс: %v", err)
+			}
+			return []Page{{Path: SearchFile, Content: append(content, '\n')}}, nil
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchPlugin(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644))
+	}
+	write("2024-12-09.md", "---\ndate: 2024-12-09\ntags: ['#daily']\n---\n# Понедельник\n")
+	write("Идея.md", "---\naliases: [Мысль, Задумка]\n---\n# Большая идея\n")
+	write("secret.md", "---\npublish: false\n---\n# Личное\n")
+
+	require.NoError(t, NewConverter().ConvertDirectory(srcDir, destDir))
+
+	content, err := os.ReadFile(filepath.Join(destDir, SearchFile))
+	require.NoError(t, err)
+	var entries []searchEntry
+	require.NoError(t, json.Unmarshal(content, &entries))
+	require.ElementsMatch(t, []searchEntry{
+		{Title: "Понедельник", URL: "2024-12-09.html", Tags: []string{"#daily"}, Date: "2024-12-09"},
+		{Title: "Большая идея", URL: "%D0%98%D0%B4%D0%B5%D1%8F.html", Aliases: []string{"Мысль", "Задумка"}},
+	}, entries)
+}
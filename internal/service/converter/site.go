@@ -0,0 +1,51 @@
+package converter
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+// SiteOptions — сведения об опубликованном сайте для лент и карты сайта
+type SiteOptions struct {
+	// Адрес, по которому опубликована целевая директория, например https://notes.example.com/daily/
+	BaseURL     string
+	Title       string
+	Description string
+	Author      string
+}
+
+// WithSite задаёт адрес и описание опубликованного сайта
+func WithSite(opts SiteOptions) Option {
+	return func(c *Converter) {
+		c.site = opts
+	}
+}
+
+// absURL возвращает адрес страницы rel (уже экранированный путь) на сайте; без BaseURL путь остаётся относительным
+func (s SiteOptions) absURL(rel string) string {
+	if s.BaseURL == "" {
+		return rel
+	}
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + rel
+}
+
+// basePath возвращает путь BaseURL без завершающего /: "/daily" для https://notes.example.com/daily/,
+// пустая строка — сайт опубликован в корне хоста
+func (s SiteOptions) basePath() string {
+	u, err := url.Parse(s.BaseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// published сообщает, публикуется ли заметка: publish: false во FrontMatter исключает её из лент
+func published(note *vault.Note) bool {
+	if note.FrontMatter == nil {
+		return true
+	}
+	publish, ok := note.FrontMatter.Extra["publish"].(bool)
+	return !ok || publish
+}
@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/sitemap"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	log "github.com/sirupsen/logrus"
+)
+
+// SitemapOptions — настройки sitemap.xml и robots.txt
+type SitemapOptions struct {
+	Enabled bool
+	Robots  bool
+	// Правила robots.txt; без правил индексация разрешена полностью
+	RobotsRules []sitemap.RobotsRule
+}
+
+// WithSitemap включает карту сайта и robots.txt
+func WithSitemap(opts SitemapOptions) Option {
+	return func(c *Converter) {
+		c.sitemap = opts
+	}
+}
+
+func sitemapPlugin(site SiteOptions, opts SitemapOptions) Plugin {
+	if !opts.Enabled && !opts.Robots {
+		return Plugin{Name: "sitemap"}
+	}
+
+	return Plugin{
+		Name: "sitemap",
+		AfterRun: func(run *Run) ([]Page, error) {
+			var pages []Page
+			sitemapURL := ""
+
+			switch {
+			case opts.Enabled && site.BaseURL == "":
+				// Протокол sitemaps.org требует абсолютных адресов
+				log.Warn("Не задан site.base_url: карта сайта не создаётся")
+			case opts.Enabled:
+				v, err := run.Vault()
+				if err != nil {
+					log.Errorf("Не удалось загрузить заметки: %v", err)
+					return nil, fmt.Errorf("не удалось загрузить заметки: %v", err)
+				}
+
+				var urls []sitemap.URL
+				for _, note := range v.Notes {
+					if !published(note) {
+						continue
+					}
+					urls = append(urls, sitemap.URL{
+						Loc:     site.absURL(escapePath(run.PageURL(note.RelPath))),
+						LastMod: lastModified(note),
+					})
+				}
+
+				files, err := sitemap.Build(urls, site.absURL)
+				if err != nil {
+					log.Errorf("Не удалось собрать карту сайта: %v", err)
+					return nil, err
+				}
+				if err := removeStaleParts(run.DestDir, files); err != nil {
+					log.Errorf("Не удалось удалить устаревшие части карты сайта: %v", err)
+					return nil, err
+				}
+				for _, f := range files {
+					pages = append(pages, Page{Path: f.Name, Content: f.Content})
+				}
+				sitemapURL = site.absURL(sitemap.FileName)
+			}
+
+			if opts.Robots {
+				base := site.basePath()
+				if base != "" {
+					log.Warnf("Поисковики читают robots.txt только из корня хоста, а сайт опубликован в %s/: перенесите %s в корень", base, sitemap.RobotsFile)
+				}
+				pages = append(pages, Page{Path: sitemap.RobotsFile, Content: sitemap.Robots(opts.RobotsRules, base, sitemapURL)})
+			}
+			return pages, nil
+		},
+	}
+}
+
+// removeStaleParts удаляет части sitemap-N.xml прежних сборок, которых нет среди новых файлов,
+// чтобы после сокращения числа адресов в dest_dir не оставались устаревшие части
+func removeStaleParts(destDir string, files []sitemap.File) error {
+	current := make(map[string]bool, len(files))
+	for _, f := range files {
+		current[f.Name] = true
+	}
+	parts, err := filepath.Glob(filepath.Join(destDir, sitemap.PartGlob))
+	if err != nil {
+		return err
+	}
+	for _, part := range parts {
+		if current[filepath.Base(part)] {
+			continue
+		}
+		if err := os.Remove(part); err != nil {
+			return fmt.Errorf("не удалось удалить %s: %v", part, err)
+		}
+		log.Debugf("Удалена устаревшая часть карты сайта %s", part)
+	}
+	return nil
+}
+
+// lastModified — дата изменения страницы: дата из FrontMatter или время изменения исходного файла
+func lastModified(note *vault.Note) time.Time {
+	if note.DateSource == vault.DateFromFrontMatter {
+		return note.Time
+	}
+	if info, err := os.Stat(note.Path); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
@@ -0,0 +1,92 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/sitemap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSitemapPlugin(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644))
+	}
+	write("2024-12-09.md", "---\ndate: 2024-12-09\n---\n# День\n")
+	write("Идея.md", "# Идея\n")
+	write("secret.md", "---\npublish: false\n---\n# Личное\n")
+	mtime := time.Date(2024, 11, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(filepath.Join(srcDir, "Идея.md"), mtime, mtime))
+
+	conv := NewConverter(
+		WithSite(SiteOptions{BaseURL: "https://notes.example.com/daily"}),
+		WithSitemap(SitemapOptions{Enabled: true, Robots: true, RobotsRules: []sitemap.RobotsRule{{Disallow: []string{"/tasks.json"}}}}),
+	)
+	require.NoError(t, conv.ConvertDirectory(srcDir, destDir))
+
+	content, err := os.ReadFile(filepath.Join(destDir, "sitemap.xml"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "<loc>https://notes.example.com/daily/2024-12-09.html</loc>\n    <lastmod>2024-12-09</lastmod>")
+	require.Contains(t, string(content), "<loc>https://notes.example.com/daily/%D0%98%D0%B4%D0%B5%D1%8F.html</loc>\n    <lastmod>"+mtime.Local().Format(time.RFC3339)+"</lastmod>")
+	require.NotContains(t, string(content), "secret")
+
+	robots, err := os.ReadFile(filepath.Join(destDir, "robots.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "User-agent: *\nDisallow: /daily/tasks.json\n\nSitemap: https://notes.example.com/daily/sitemap.xml\n", string(robots))
+}
+
+func TestSitemapPlugin_RemovesStaleParts(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a.md"), []byte("# A\n"), 0644))
+	// Части индекса от прежней сборки, когда адресов было больше
+	for _, name := range []string{"sitemap-1.xml", "sitemap-2.xml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(destDir, name), []byte("<urlset/>"), 0644))
+	}
+
+	conv := NewConverter(
+		WithSite(SiteOptions{BaseURL: "https://notes.example.com/"}),
+		WithSitemap(SitemapOptions{Enabled: true}),
+	)
+	require.NoError(t, conv.ConvertDirectory(srcDir, destDir))
+
+	require.FileExists(t, filepath.Join(destDir, "sitemap.xml"))
+	require.NoFileExists(t, filepath.Join(destDir, "sitemap-1.xml"))
+	require.NoFileExists(t, filepath.Join(destDir, "sitemap-2.xml"))
+}
+
+func TestSitemapPlugin_NoBaseURL(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a.md"), []byte("# A\n"), 0644))
+
+	require.NoError(t, NewConverter(WithSitemap(SitemapOptions{Enabled: true, Robots: true})).ConvertDirectory(srcDir, destDir))
+
+	require.NoFileExists(t, filepath.Join(destDir, "sitemap.xml"))
+	robots, err := os.ReadFile(filepath.Join(destDir, "robots.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "User-agent: *\nDisallow:\n", string(robots))
+}
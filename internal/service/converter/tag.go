@@ -0,0 +1,63 @@
+package converter
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Тег в тексте так же, как его находит разбор хранилища: #tag, #project/alpha, #заметки
+var tagRe = regexp.MustCompile(`(?:^|\s)(#[\p{L}\p{N}_/-]+)`)
+
+// tagPlugin оборачивает теги в тексте в <span class="tag">; код и ссылки не трогает
+func tagPlugin() Plugin {
+	return Plugin{
+		Name:         "tags",
+		TransformAST: renderTags,
+	}
+}
+
+func renderTags(ctx *Context, doc ast.Node, source []byte) {
+	var texts []*ast.Text
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.CodeSpan, *ast.Link, *ast.AutoLink, *ast.Image:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			texts = append(texts, n)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, t := range texts {
+		value := t.Segment.Value(source)
+		matches := tagRe.FindAllSubmatchIndex(value, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		parent := t.Parent()
+		start := t.Segment.Start
+		last := 0
+		for _, m := range matches {
+			if m[2] > last {
+				parent.InsertBefore(parent, t, ast.NewTextSegment(text.NewSegment(start+last, start+m[2])))
+			}
+			tag := ast.NewString([]byte(fmt.Sprintf(`<span class="tag">%s</span>`, html.EscapeString(string(value[m[2]:m[3]])))))
+			tag.SetCode(true)
+			parent.InsertBefore(parent, t, tag)
+			last = m[3]
+		}
+		// Остаток текста сохраняет переносы строк исходного узла
+		rest := ast.NewTextSegment(text.NewSegment(start+last, t.Segment.Stop))
+		rest.SetSoftLineBreak(t.SoftLineBreak())
+		rest.SetHardLineBreak(t.HardLineBreak())
+		parent.ReplaceChild(parent, t, rest)
+	}
+}
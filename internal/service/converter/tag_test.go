@@ -0,0 +1,18 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagPlugin(t *testing.T) {
+	sut := NewConverter()
+
+	got, err := sut.renderMarkdown(sut.newContext("", nil),
+		[]byte("Идея #work и #проект/альфа\nC# не тег\n\n`#code` [#link](x.html)"))
+
+	require.NoError(t, err)
+	require.Equal(t, "<p>Идея <span class=\"tag\">#work</span> и <span class=\"tag\">#проект/альфа</span>\nC# не тег</p>\n"+
+		"<p><code>#code</code> <a href=\"x.html\">#link</a></p>\n", string(got))
+}
@@ -0,0 +1,173 @@
+package converter
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/page"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Строка [TOC] в заметке заменяется оглавлением
+const tocPlaceholder = "TOC-BLOCK"
+
+var (
+	tocMarkerRe      = regexp.MustCompile(`(?i)^\s*\[toc\]\s*$`)
+	tocPlaceholderRe = regexp.MustCompile(`(?:<p>)?TOC-BLOCK(?:</p>)?`)
+)
+
+// TOCOptions — настройки оглавления
+type TOCOptions struct {
+	// Оглавление выводится перед текстом заметки, если в ней не меньше MinHeadings заголовков
+	// и нет маркера [TOC]; 0 — только по маркеру
+	MinHeadings int
+}
+
+// WithTOC задаёт, когда страница заметки получает оглавление
+func WithTOC(opts TOCOptions) Option {
+	return func(c *Converter) {
+		c.toc = opts
+	}
+}
+
+type tocEntry struct {
+	level int
+	title string
+	id    string
+}
+
+// tocPlugin даёт заголовкам читаемые уникальные id и ссылки-якоря и собирает из них оглавление.
+// id назначаются по тексту заголовка так же, как vault.Heading.ID, поэтому ссылки [[Заметка#Раздел]] совпадают с ними
+func tocPlugin(opts TOCOptions) Plugin {
+	return Plugin{
+		Name: "toc",
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			lines := strings.Split(string(md), "\n")
+			found := false
+			for i, line := range lines {
+				if tocMarkerRe.MatchString(line) {
+					lines[i] = "\n" + tocPlaceholder + "\n"
+					found = true
+				}
+			}
+			if !found {
+				return md, nil
+			}
+			ctx.Set("toc-marker", true)
+			return []byte(strings.Join(lines, "\n")), nil
+		},
+		TransformAST: func(ctx *Context, doc ast.Node, source []byte) {
+			slugger, _ := ctx.Get("toc-slugger").(*vault.Slugger)
+			if slugger == nil {
+				slugger = vault.NewSlugger()
+				ctx.Set("toc-slugger", slugger)
+			}
+			entries := anchorHeadings(doc, source, slugger)
+			// Выноски и другие фрагменты отрисовываются после текста заметки: их заголовки
+			// получают якоря, но в оглавление не попадают
+			if ctx.Get("toc") == nil {
+				ctx.Set("toc", entries)
+			}
+		},
+		PostRender: func(ctx *Context, html []byte) ([]byte, error) {
+			entries, _ := ctx.Get("toc").([]tocEntry)
+			items := tocTree(entries)
+			if marker, _ := ctx.Get("toc-marker").(bool); marker {
+				toc := ""
+				if len(items) > 0 {
+					rendered, err := page.TOC(items)
+					if err != nil {
+						return nil, err
+					}
+					toc = string(rendered)
+				}
+				return tocPlaceholderRe.ReplaceAll(html, []byte(toc)), nil
+			}
+			if opts.MinHeadings > 0 && len(entries) >= opts.MinHeadings {
+				ctx.TOC = items
+			}
+			return html, nil
+		},
+	}
+}
+
+// anchorHeadings назначает заголовкам id и добавляет к ним ссылку-якорь
+func anchorHeadings(doc ast.Node, source []byte, slugger *vault.Slugger) []tocEntry {
+	var headings []*ast.Heading
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if h, ok := n.(*ast.Heading); ok && entering {
+			headings = append(headings, h)
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	entries := make([]tocEntry, 0, len(headings))
+	for _, h := range headings {
+		title := strings.TrimSpace(plainText(h, source))
+		id := slugger.Unique(title)
+		h.SetAttributeString("id", []byte(id))
+
+		anchor := ast.NewString([]byte(fmt.Sprintf(` <a class="heading-anchor" href="#%s" aria-hidden="true">#</a>`, url.PathEscape(id))))
+		anchor.SetCode(true)
+		h.AppendChild(h, anchor)
+
+		entries = append(entries, tocEntry{level: h.Level, title: title, id: id})
+	}
+	return entries
+}
+
+// plainText собирает видимый текст узла без HTML-тегов
+func plainText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			b.Write(c.Segment.Value(source))
+			if c.SoftLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			if !c.IsCode() {
+				b.Write(c.Value)
+			}
+		case *ast.RawHTML:
+		default:
+			b.WriteString(plainText(c, source))
+		}
+	}
+	return b.String()
+}
+
+// tocTree вкладывает заголовки в предыдущие заголовки более высокого уровня
+func tocTree(entries []tocEntry) []page.TOCItem {
+	if len(entries) == 0 {
+		return nil
+	}
+	var items []page.TOCItem
+	for i := 0; i < len(entries); {
+		var level []page.TOCItem
+		level, i = tocLevel(entries, i)
+		items = append(items, level...)
+	}
+	return items
+}
+
+func tocLevel(entries []tocEntry, i int) ([]page.TOCItem, int) {
+	level := entries[i].level
+	var items []page.TOCItem
+	for i < len(entries) && entries[i].level == level {
+		item := page.TOCItem{Title: entries[i].title, ID: entries[i].id}
+		i++
+		for i < len(entries) && entries[i].level > level {
+			var children []page.TOCItem
+			children, i = tocLevel(entries, i)
+			item.Children = append(item.Children, children...)
+		}
+		items = append(items, item)
+	}
+	return items, i
+}
@@ -0,0 +1,78 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/page"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOCPlugin_Marker(t *testing.T) {
+	sut := NewConverter()
+	md := "# День\n\n[TOC]\n\n## Итоги\n\n### `Код` и **жирный**\n\n## Итоги\n\n> [!note]\n> ## Итоги\n"
+
+	out, err := sut.RenderNote(&vault.Note{Body: []byte(md)}, "")
+
+	require.NoError(t, err)
+	html := string(out)
+	require.Contains(t, html, `<h1 id="день">День <a class="heading-anchor" href="#%D0%B4%D0%B5%D0%BD%D1%8C" aria-hidden="true">#</a></h1>`)
+	require.Contains(t, html, `<h2 id="итоги">`)
+	require.Contains(t, html, `<h3 id="код-и-жирный"><code>Код</code> и <strong>жирный</strong> <a class="heading-anchor"`)
+	require.Contains(t, html, `<h2 id="итоги-1">`)
+	// Заголовок в выноске получает уникальный id, но в оглавление не попадает
+	require.Contains(t, html, `<h2 id="итоги-2">`)
+	require.NotContains(t, html, "TOC-BLOCK")
+	require.NotContains(t, html, "[TOC]")
+
+	toc, err := page.TOC([]page.TOCItem{{Title: "День", ID: "день", Children: []page.TOCItem{
+		{Title: "Итоги", ID: "итоги", Children: []page.TOCItem{{Title: "Код и жирный", ID: "код-и-жирный"}}},
+		{Title: "Итоги", ID: "итоги-1"},
+	}}})
+	require.NoError(t, err)
+	require.Contains(t, html, string(toc))
+}
+
+func TestTOCPlugin_Page(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "long.md"), []byte("# День\n## Утро\n## Вечер"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "short.md"), []byte("# День\n## Утро"), 0644))
+
+	sut := NewConverter(WithTOC(TOCOptions{MinHeadings: 3}))
+	require.NoError(t, sut.ConvertDirectory(srcDir, destDir))
+
+	long, err := os.ReadFile(filepath.Join(destDir, "long.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(long), `<nav class="toc">`)
+	require.Contains(t, string(long), `<li><a href="#%d1%83%d1%82%d1%80%d0%be">Утро</a></li>`)
+
+	short, err := os.ReadFile(filepath.Join(destDir, "short.html"))
+	require.NoError(t, err)
+	require.NotContains(t, string(short), `<nav class="toc">`)
+}
+
+func TestTOCTree(t *testing.T) {
+	entries := []tocEntry{
+		{level: 2, title: "a", id: "a"},
+		{level: 1, title: "b", id: "b"},
+		{level: 3, title: "c", id: "c"},
+		{level: 2, title: "d", id: "d"},
+		{level: 1, title: "e", id: "e"},
+	}
+
+	require.Equal(t, []page.TOCItem{
+		{Title: "a", ID: "a"},
+		{Title: "b", ID: "b", Children: []page.TOCItem{{Title: "c", ID: "c"}, {Title: "d", ID: "d"}}},
+		{Title: "e", ID: "e"},
+	}, tocTree(entries))
+}
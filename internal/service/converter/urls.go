@@ -0,0 +1,155 @@
+package converter
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	log "github.com/sirupsen/logrus"
+)
+
+// Способы именования страниц заметок
+const (
+	// URLName — имя исходного файла: «Мой день.md» → «Мой день.html»
+	URLName = "name"
+	// URLTranslit — транслитерация имени в латинский слаг: «Мой день.md» → «moy-den.html»
+	URLTranslit = "translit"
+	// URLDate — путь по дате из FrontMatter: 2024/12/09/index.html; заметки без даты — как URLTranslit
+	URLDate = "date"
+)
+
+// URLOptions — настройки путей к страницам заметок. Поля FrontMatter permalink (полный путь)
+// и slug (имя страницы) важнее способа из настроек
+type URLOptions struct {
+	Style string
+}
+
+// WithURLs задаёт способ построения путей к страницам заметок
+func WithURLs(opts URLOptions) Option {
+	return func(c *Converter) {
+		if opts.Style != URLName && opts.Style != URLTranslit && opts.Style != URLDate {
+			if opts.Style != "" {
+				log.Warnf("Неизвестный способ построения URL %s, используется %s", opts.Style, URLName)
+			}
+			opts.Style = URLName
+		}
+		c.urls = opts
+	}
+}
+
+var translitTable = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// translitSlug превращает имя в слаг из латинских букв, цифр и дефисов: «Мой день 2024-12-09» → «moy-den-2024-12-09»
+func translitSlug(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if latin, ok := translitTable[r]; ok {
+			b.WriteString(latin)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return vault.Slug(b.String())
+}
+
+// notePath возвращает путь страницы заметки относительно целевой директории через «/»
+func notePath(note *vault.Note, style string) string {
+	if permalink := frontMatterString(note, "permalink"); permalink != "" {
+		return permalinkPath(permalink)
+	}
+	slug := strings.Trim(path.Clean("/"+frontMatterString(note, "slug")), "/")
+
+	switch style {
+	case URLDate:
+		if date, ok := note.Date(); ok {
+			if slug != "" {
+				return date.Format("2006/01/02/") + slug + ".html"
+			}
+			return date.Format("2006/01/02/") + "index.html"
+		}
+		fallthrough
+	case URLTranslit:
+		if slug == "" {
+			slug = translitSlug(note.Name)
+		}
+		return slug + ".html"
+	}
+	if slug != "" {
+		return slug + ".html"
+	}
+	return htmlFileName(note.RelPath)
+}
+
+// permalinkPath превращает permalink в путь файла: /notes/day/ и /notes/day → notes/day/index.html
+func permalinkPath(permalink string) string {
+	p := strings.Trim(path.Clean("/"+permalink), "/")
+	if p == "" {
+		return "index.html"
+	}
+	if strings.HasSuffix(strings.ToLower(p), ".html") {
+		return p
+	}
+	return p + "/index.html"
+}
+
+func frontMatterString(note *vault.Note, key string) string {
+	if note.FrontMatter == nil {
+		return ""
+	}
+	s, _ := note.FrontMatter.Extra[key].(string)
+	return strings.TrimSpace(s)
+}
+
+// aliasPath — путь страницы-перенаправления для псевдонима заметки
+func aliasPath(alias, style string) string {
+	if style == URLName {
+		return htmlFileName(alias + ".md")
+	}
+	return translitSlug(alias) + ".html"
+}
+
+// buildPaths назначает страницы всем заметкам хранилища. Если путь уже занят другой заметкой,
+// к имени добавляется номер, чтобы страницы не затирали друг друга
+func buildPaths(v *vault.Vault, style string) map[string]string {
+	paths := make(map[string]string, len(v.Notes))
+	taken := make(map[string]string, len(v.Notes))
+	for _, note := range v.Notes {
+		p := notePath(note, style)
+		unique := p
+		for i := 1; taken[strings.ToLower(unique)] != ""; i++ {
+			unique = numberedPath(p, i)
+		}
+		if unique != p {
+			log.Warnf("Страница %s заметки %s уже занята заметкой %s, используется %s", p, note.RelPath, taken[strings.ToLower(p)], unique)
+		}
+		taken[strings.ToLower(unique)] = note.RelPath
+		paths[note.RelPath] = unique
+	}
+	return paths
+}
+
+// numberedPath добавляет номер к имени страницы, а для index.html — к имени директории
+func numberedPath(p string, i int) string {
+	dir, file := path.Split(p)
+	if file == "index.html" && dir != "" {
+		return fmt.Sprintf("%s-%d/index.html", strings.TrimSuffix(dir, "/"), i)
+	}
+	return fmt.Sprintf("%s%s-%d.html", dir, strings.TrimSuffix(file, ".html"), i)
+}
+
+// relURL возвращает путь от страницы from до файла to; оба пути относительно целевой директории
+func relURL(from, to string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(to))
+	if err != nil {
+		return to
+	}
+	return filepath.ToSlash(rel)
+}
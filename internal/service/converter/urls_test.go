@@ -0,0 +1,109 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslitSlug(t *testing.T) {
+	tests := map[string]string{
+		"Мой день 2024-12-09": "moy-den-2024-12-09",
+		"Щука и ёж":           "shchuka-i-yozh",
+		"Подъём, объём!":      "podyom-obyom",
+		"Go & Rust":           "go-rust",
+	}
+	for name, want := range tests {
+		require.Equal(t, want, translitSlug(name), name)
+	}
+}
+
+func TestNotePath(t *testing.T) {
+	note := func(date string, extra map[string]any) *vault.Note {
+		return &vault.Note{
+			RelPath:     "daily/Мой день.md",
+			Name:        "Мой день",
+			FrontMatter: &vault.FrontMatter{Date: date, Extra: extra},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		note  *vault.Note
+		style string
+		want  string
+	}{
+		{name: "Имя файла", note: note("2024-12-09", nil), style: URLName, want: "Мой день.html"},
+		{name: "Транслитерация", note: note("", nil), style: URLTranslit, want: "moy-den.html"},
+		{name: "Дата", note: note("2024-12-09", nil), style: URLDate, want: "2024/12/09/index.html"},
+		{name: "Дата и slug", note: note("2024-12-09", map[string]any{"slug": "itogi"}), style: URLDate, want: "2024/12/09/itogi.html"},
+		{name: "Без даты", note: note("", nil), style: URLDate, want: "moy-den.html"},
+		{name: "slug", note: note("", map[string]any{"slug": "my-day"}), style: URLName, want: "my-day.html"},
+		{name: "permalink-директория", note: note("2024-12-09", map[string]any{"permalink": "/about/me/"}), style: URLDate, want: "about/me/index.html"},
+		{name: "permalink-файл", note: note("", map[string]any{"permalink": "../../about.html"}), style: URLName, want: "about.html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, notePath(tt.note, tt.style))
+		})
+	}
+}
+
+func TestBuildPaths_Conflicts(t *testing.T) {
+	v := vault.New("", []*vault.Note{
+		{RelPath: "a/day.md", Name: "day", FrontMatter: &vault.FrontMatter{Date: "2024-12-09"}},
+		{RelPath: "b/day.md", Name: "day", FrontMatter: &vault.FrontMatter{Date: "2024-12-09"}},
+		{RelPath: "c/Day.md", Name: "Day"},
+	})
+
+	require.Equal(t, map[string]string{"a/day.md": "day.html", "b/day.md": "day-1.html", "c/Day.md": "Day-2.html"}, buildPaths(v, URLName))
+	require.Equal(t, "2024/12/09-1/index.html", buildPaths(v, URLDate)["b/day.md"])
+}
+
+func TestRelURL(t *testing.T) {
+	require.Equal(t, "b.html", relURL("a.html", "b.html"))
+	require.Equal(t, "../../../b.html", relURL("2024/12/09/index.html", "b.html"))
+	require.Equal(t, "../10/index.html", relURL("2024/12/09/index.html", "2024/12/10/index.html"))
+	require.Equal(t, "2024/12/09/index.html", relURL("b.html", "2024/12/09/index.html"))
+}
+
+func TestConvertDirectory_DateURLs(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	destDir, err := os.MkdirTemp("", "dest_dir")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(srcDir)
+		_ = os.RemoveAll(destDir)
+	}()
+
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644))
+	}
+	write("Мой день 2024-12-09.md", "---\ndate: 2024-12-09\naliases: [Понедельник]\n---\nСм. [[Идеи]] и ![[схема.png]]\n")
+	write("Идеи.md", "Назад: [[Мой день 2024-12-09#Итоги]]\n")
+
+	sut := NewConverter(WithURLs(URLOptions{Style: URLDate}))
+	require.NoError(t, sut.ConvertDirectory(srcDir, destDir))
+
+	day, err := os.ReadFile(filepath.Join(destDir, "2024", "12", "09", "index.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(day), `<a class="internal-link" href="../../../idei.html">Идеи</a>`)
+	require.Contains(t, string(day), `<img class="internal-embed" src="../../../%D1%81%D1%85%D0%B5%D0%BC%D0%B0.png" alt="схема.png">`)
+
+	ideas, err := os.ReadFile(filepath.Join(destDir, "idei.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(ideas), `href="2024/12/09/index.html#%D0%B8%D1%82%D0%BE%D0%B3%D0%B8"`)
+
+	redirect, err := os.ReadFile(filepath.Join(destDir, "ponedelnik.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(redirect), `url=2024/12/09/index.html`)
+
+	dashboard, err := os.ReadFile(filepath.Join(destDir, "dashboard.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(dashboard), `href="idei.html"`)
+}
@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/dashboard"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/tasks"
+	log "github.com/sirupsen/logrus"
+)
+
+// Дашборд и задачи зависят от всех заметок сразу, поэтому пересобираются
+// после каждого запуска, а не только при изменении одного файла
+
+func dashboardPlugin() Plugin {
+	return Plugin{
+		Name: "dashboard",
+		AfterRun: func(run *Run) ([]Page, error) {
+			v, err := run.Vault()
+			if err != nil {
+				log.Errorf("Не удалось загрузить заметки: %v", err)
+				return nil, fmt.Errorf("не удалось загрузить заметки: %v", err)
+			}
+
+			content, err := dashboard.Build(v, run.Now, run.PageURL)
+			if err != nil {
+				log.Errorf("Не удалось собрать дашборд: %v", err)
+				return nil, fmt.Errorf("не удалось собрать дашборд: %v", err)
+			}
+			return []Page{{Path: dashboard.FileName, Content: content}}, nil
+		},
+	}
+}
+
+func tasksPlugin() Plugin {
+	return Plugin{
+		Name: "tasks",
+		AfterRun: func(run *Run) ([]Page, error) {
+			v, err := run.Vault()
+			if err != nil {
+				log.Errorf("Не удалось загрузить заметки: %v", err)
+				return nil, fmt.Errorf("не удалось загрузить заметки: %v", err)
+			}
+
+			entries := tasks.Collect(v, run.Now)
+
+			content, err := tasks.BuildPage(entries, run.Now, run.PageURL)
+			if err != nil {
+				log.Errorf("Не удалось собрать страницу задач: %v", err)
+				return nil, fmt.Errorf("не удалось собрать страницу задач: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := tasks.WriteJSON(&buf, entries); err != nil {
+				return nil, err
+			}
+			return []Page{
+				{Path: tasks.PageFileName, Content: content},
+				{Path: tasks.JSONFileName, Content: buf.Bytes()},
+			}, nil
+		},
+	}
+}
+
+func writePage(path string, content []byte) error {
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		log.Errorf("Не удалось записать файл %s: %v", path, err)
+		return fmt.Errorf("не удалось записать файл %s: %v", path, err)
+	}
+
+	log.WithFields(log.Fields{
+		"file": path,
+	}).Info("Файл успешно записан")
+
+	return nil
+}
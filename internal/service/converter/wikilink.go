@@ -0,0 +1,130 @@
+package converter
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	log "github.com/sirupsen/logrus"
+)
+
+// [[Заметка]], [[Заметка#Раздел|Текст]], ![[картинка.png|300]]
+var wikiLinkRe = regexp.MustCompile(`(!?)\[\[([^\[\]|#]*)(#[^\[\]|]*)?(?:\|([^\[\]]*))?\]\]`)
+
+var imageExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true, ".bmp": true,
+}
+
+func wikilinkPlugin() Plugin {
+	return Plugin{
+		Name: "wikilinks",
+		PreParse: func(ctx *Context, md []byte) ([]byte, error) {
+			return renderWikiLinks(md, noteResolver(ctx), ctx.AssetURL), nil
+		},
+	}
+}
+
+// noteResolver возвращает ссылку со страницы текущей заметки на заметку, куда ведёт вики-ссылка,
+// с учётом псевдонимов из aliases. Если заметки нет или хранилище недоступно, например для заметки
+// вне ConvertDirectory, ссылка строится по имени цели
+func noteResolver(ctx *Context) func(target string) string {
+	var v *vault.Vault
+	if ctx.SrcDir != "" {
+		var err error
+		if v, err = ctx.Vault(); err != nil {
+			log.Debugf("Не удалось загрузить заметки для разрешения вики-ссылок: %v", err)
+		}
+	}
+	return func(target string) string {
+		if v != nil {
+			if note := v.Resolve(ctx.Note, vault.Link{Target: target, Wiki: true}); note != nil {
+				return ctx.PageURL(note.RelPath)
+			}
+		}
+		return ctx.AssetURL(ctx.conv.pageURL("", strings.TrimSuffix(target, ".md")+".md"))
+	}
+}
+
+// renderWikiLinks заменяет вики-ссылки ссылками на HTML-страницы заметок, а встраивания картинок — на <img>.
+// note возвращает ссылку на страницу заметки по цели ссылки, asset — на файл в корне целевой директории;
+// без них ссылки строятся по имени относительно текущей страницы
+func renderWikiLinks(md []byte, note, asset func(string) string) []byte {
+	if note == nil {
+		note = func(target string) string { return htmlFileName(strings.TrimSuffix(target, ".md") + ".md") }
+	}
+	if asset == nil {
+		asset = func(name string) string { return name }
+	}
+	lines := strings.Split(string(md), "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		lines[i] = replaceOutsideCode(line, func(text string) string {
+			return wikiLinkRe.ReplaceAllStringFunc(text, func(match string) string {
+				m := wikiLinkRe.FindStringSubmatch(match)
+				return wikiLinkHTML(m[1] == "!", strings.TrimSpace(m[2]), m[3], m[4], note, asset)
+			})
+		})
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func wikiLinkHTML(embed bool, target, anchor, text string, note, asset func(string) string) string {
+	ext := strings.ToLower(path.Ext(target))
+	if embed && imageExts[ext] {
+		src := escapePath(asset(target))
+		// ![[картинка.png|300]] задаёт ширину, как в Obsidian
+		if text != "" && strings.Trim(text, "0123456789") == "" {
+			return fmt.Sprintf(`<img class="internal-embed" src="%s" alt="%s" width="%s">`, src, html.EscapeString(target), text)
+		}
+		alt := target
+		if text != "" {
+			alt = text
+		}
+		return fmt.Sprintf(`<img class="internal-embed" src="%s" alt="%s">`, src, html.EscapeString(alt))
+	}
+
+	href := ""
+	if target != "" {
+		if ext == "" || ext == ".md" {
+			href = escapePath(note(target))
+		} else {
+			href = escapePath(asset(target))
+		}
+	}
+	if anchor = strings.TrimPrefix(anchor, "#"); anchor != "" {
+		// [[Заметка#^id]] ведёт к блоку с id, [[Заметка#Раздел]] — к заголовку
+		if strings.HasPrefix(anchor, "^") {
+			href += "#" + url.PathEscape(anchor[1:])
+		} else {
+			href += "#" + url.PathEscape(vault.Slug(anchor))
+		}
+	}
+
+	if text == "" {
+		text = target
+		if anchor != "" {
+			text = strings.TrimPrefix(target+" > "+anchor, " > ")
+		}
+	}
+	return fmt.Sprintf(`<a class="internal-link" href="%s">%s</a>`, href, html.EscapeString(text))
+}
+
+func escapePath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
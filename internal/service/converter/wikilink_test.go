@@ -0,0 +1,57 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWikiLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{
+			name: "Ссылка на заметку",
+			md:   "См. [[Идеи]]",
+			want: `См. <a class="internal-link" href="%D0%98%D0%B4%D0%B5%D0%B8.html">Идеи</a>`,
+		},
+		{
+			name: "Путь, раздел и текст",
+			md:   "[[projects/plan.md#Итоги|план]]",
+			want: `<a class="internal-link" href="plan.html#%D0%B8%D1%82%D0%BE%D0%B3%D0%B8">план</a>`,
+		},
+		{
+			name: "Раздел без текста",
+			md:   "[[plan#Итоги]]",
+			want: `<a class="internal-link" href="plan.html#%D0%B8%D1%82%D0%BE%D0%B3%D0%B8">plan &gt; Итоги</a>`,
+		},
+		{
+			name: "Раздел текущей заметки",
+			md:   "[[#Итоги]]",
+			want: `<a class="internal-link" href="#%D0%B8%D1%82%D0%BE%D0%B3%D0%B8">Итоги</a>`,
+		},
+		{
+			name: "Картинка с шириной",
+			md:   "![[img/cat.png|300]]",
+			want: `<img class="internal-embed" src="img/cat.png" alt="img/cat.png" width="300">`,
+		},
+		{
+			name: "Встраивание заметки становится ссылкой",
+			md:   "![[plan]]",
+			want: `<a class="internal-link" href="plan.html">plan</a>`,
+		},
+		{
+			name: "Код не трогается",
+			md:   "`[[plan]]`\n```\n[[plan]]\n```",
+			want: "`[[plan]]`\n```\n[[plan]]\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, string(renderWikiLinks([]byte(tt.md), nil, nil)))
+		})
+	}
+}
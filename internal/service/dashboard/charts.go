@@ -0,0 +1,177 @@
+package dashboard
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	chartWidth   = 720
+	chartHeight  = 220
+	chartPadding = 32
+	barHeight    = 18
+	labelWidth   = 200
+)
+
+var palette = []string{"#0969da", "#1a7f37", "#bf3989", "#9a6700", "#8250df", "#cf222e"}
+
+type series struct {
+	Name   string
+	Values []int
+}
+
+// lineChartSVG рисует несколько рядов значений по общей оси периодов
+func lineChartSVG(periods []string, data []series) string {
+	if len(periods) == 0 || len(data) == 0 {
+		return ""
+	}
+
+	maxValue := 1
+	for _, s := range data {
+		for _, v := range s.Values {
+			maxValue = max(maxValue, v)
+		}
+	}
+
+	plotW := chartWidth - 2*chartPadding
+	plotH := chartHeight - 2*chartPadding
+	x := func(i int) int {
+		if len(periods) == 1 {
+			return chartPadding + plotW/2
+		}
+		return chartPadding + i*plotW/(len(periods)-1)
+	}
+	y := func(v int) int {
+		return chartPadding + plotH - v*plotH/maxValue
+	}
+
+	var b strings.Builder
+	openSVG(&b, chartWidth, chartHeight+16*len(data), "График")
+	axes(&b, periods, x, maxValue)
+
+	for i, s := range data {
+		color := palette[i%len(palette)]
+		points := make([]string, len(s.Values))
+		for j, v := range s.Values {
+			points[j] = fmt.Sprintf("%d,%d", x(j), y(v))
+			fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="3" fill="%s"><title>%s</title></circle>`,
+				x(j), y(v), color, html.EscapeString(fmt.Sprintf("%s, %s: %d", s.Name, periods[j], v)))
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`, color, strings.Join(points, " "))
+		legend(&b, chartHeight+16*i, color, s.Name)
+	}
+
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// stackedBarsSVG рисует столбцы, сложенные из нескольких рядов
+func stackedBarsSVG(periods []string, data []series) string {
+	if len(periods) == 0 || len(data) == 0 {
+		return ""
+	}
+
+	maxValue := 1
+	for i := range periods {
+		total := 0
+		for _, s := range data {
+			total += s.Values[i]
+		}
+		maxValue = max(maxValue, total)
+	}
+
+	plotW := chartWidth - 2*chartPadding
+	plotH := chartHeight - 2*chartPadding
+	slot := plotW / len(periods)
+	barW := max(slot*2/3, 2)
+	x := func(i int) int {
+		return chartPadding + i*slot + slot/2
+	}
+
+	var b strings.Builder
+	openSVG(&b, chartWidth, chartHeight+16*len(data), "Диаграмма")
+	axes(&b, periods, x, maxValue)
+
+	for i := range periods {
+		base := chartPadding + plotH
+		for j, s := range data {
+			h := s.Values[i] * plotH / maxValue
+			if h == 0 {
+				continue
+			}
+			base -= h
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"><title>%s</title></rect>`,
+				x(i)-barW/2, base, barW, h, palette[j%len(palette)],
+				html.EscapeString(fmt.Sprintf("%s, %s: %d", s.Name, periods[i], s.Values[i])))
+		}
+	}
+	for j, s := range data {
+		legend(&b, chartHeight+16*j, palette[j%len(palette)], s.Name)
+	}
+
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+type bar struct {
+	Label string
+	Href  string
+	Value int
+}
+
+// horizontalBarsSVG рисует рейтинг: подпись слева и полосу пропорционально значению
+func horizontalBarsSVG(bars []bar) string {
+	if len(bars) == 0 {
+		return ""
+	}
+
+	maxValue := 1
+	for _, br := range bars {
+		maxValue = max(maxValue, br.Value)
+	}
+
+	plotW := chartWidth - labelWidth - chartPadding
+	height := len(bars) * (barHeight + 6)
+
+	var b strings.Builder
+	openSVG(&b, chartWidth, height, "Рейтинг")
+	for i, br := range bars {
+		y := i * (barHeight + 6)
+		label := html.EscapeString(br.Label)
+		if br.Href != "" {
+			fmt.Fprintf(&b, `<a href="%s"><text x="0" y="%d">%s</text></a>`, html.EscapeString(br.Href), y+barHeight-5, label)
+		} else {
+			fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`, y+barHeight-5, label)
+		}
+		w := max(br.Value*plotW/maxValue, 1)
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, labelWidth, y, w, barHeight, palette[0])
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%d</text>`, labelWidth+w+4, y+barHeight-5, br.Value)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+func openSVG(b *strings.Builder, width, height int, label string) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" role="img" aria-label="%s">`, width, height, label)
+}
+
+// axes рисует оси, подписи периодов и максимальное значение
+func axes(b *strings.Builder, periods []string, x func(int) int, maxValue int) {
+	bottom := chartHeight - chartPadding
+	fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#d0d7de"/>`, chartPadding, bottom, chartWidth-chartPadding, bottom)
+	fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#d0d7de"/>`, chartPadding, chartPadding, chartPadding, bottom)
+	fmt.Fprintf(b, `<text x="0" y="%d">%d</text>`, chartPadding+4, maxValue)
+	fmt.Fprintf(b, `<text x="0" y="%d">0</text>`, bottom)
+
+	// Подписываем не больше ~12 периодов, чтобы текст не слипался
+	step := max(len(periods)/12, 1)
+	for i := 0; i < len(periods); i += step {
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`, x(i), bottom+14, html.EscapeString(periods[i]))
+	}
+}
+
+func legend(b *strings.Builder, y int, color, name string) {
+	fmt.Fprintf(b, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`, chartPadding, y, color)
+	fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`, chartPadding+16, y+9, html.EscapeString(name))
+}
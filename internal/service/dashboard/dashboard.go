@@ -0,0 +1,105 @@
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/analytics"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/page"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+const (
+	FileName = "dashboard.html"
+
+	// Сколько тегов показывать на графике трендов
+	topTagsLimit = 5
+)
+
+// Build собирает страницу с аналитикой по хранилищу.
+// now задаёт текущий день для подсчёта текущей серии,
+// href превращает относительный путь заметки в ссылку на её HTML-файл
+func Build(v *vault.Vault, now time.Time, href func(relPath string) string) ([]byte, error) {
+	r := analytics.BuildReport(v, now)
+
+	var body strings.Builder
+	fmt.Fprintf(&body,
+		`<p>Заметок: %d, закрыто: %d, открыто: %d. Слов: %d (в среднем %.0f). Самая длинная серия: %d дн., текущая: %d дн.</p>`,
+		r.TotalNotes, r.ClosedNotes, r.OpenNotes, r.TotalWords, r.AverageWords, r.LongestStreak, r.CurrentStreak,
+	)
+
+	section(&body, "Активность", heatmapSVG(r.PerDay, r.LastDate))
+	section(&body, "Теги по месяцам", tagTrendsSVG(r))
+	section(&body, "Открытые и закрытые заметки", statusSVG(r))
+	section(&body, "Самые цитируемые заметки", topLinkedSVG(r, href))
+
+	var buf bytes.Buffer
+	err := page.Render(&buf, page.Data{
+		Title: "Дашборд",
+		Body:  template.HTML(body.String()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func section(b *strings.Builder, title, svg string) {
+	fmt.Fprintf(b, "<h2>%s</h2>\n", html.EscapeString(title))
+	if svg == "" {
+		b.WriteString("<p>Нет данных</p>\n")
+		return
+	}
+	fmt.Fprintf(b, "<div class=\"chart\">%s</div>\n", svg)
+}
+
+func tagTrendsSVG(r *analytics.Report) string {
+	periods := months(r)
+	index := make(map[string]int, len(periods))
+	for i, p := range periods {
+		index[p] = i
+	}
+
+	var data []series
+	for _, tag := range r.Tags[:min(topTagsLimit, len(r.Tags))] {
+		values := make([]int, len(periods))
+		for _, m := range tag.Months {
+			values[index[m.Period]] = m.Notes
+		}
+		data = append(data, series{Name: tag.Tag, Values: values})
+	}
+
+	return lineChartSVG(periods, data)
+}
+
+func statusSVG(r *analytics.Report) string {
+	closed := series{Name: "Закрытые"}
+	open := series{Name: "Открытые"}
+	var periods []string
+	for _, sc := range r.StatusByMonth {
+		periods = append(periods, sc.Period)
+		closed.Values = append(closed.Values, sc.Closed)
+		open.Values = append(open.Values, sc.Open)
+	}
+	return stackedBarsSVG(periods, []series{closed, open})
+}
+
+func topLinkedSVG(r *analytics.Report, href func(string) string) string {
+	bars := make([]bar, 0, len(r.TopLinked))
+	for _, l := range r.TopLinked {
+		bars = append(bars, bar{Label: l.Note, Href: href(l.Path), Value: l.Incoming})
+	}
+	return horizontalBarsSVG(bars)
+}
+
+func months(r *analytics.Report) []string {
+	periods := make([]string, len(r.PerMonth))
+	for i, m := range r.PerMonth {
+		periods[i] = m.Period
+	}
+	return periods
+}
@@ -0,0 +1,73 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/analytics"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	v := vault.New("", []*vault.Note{
+		{
+			Name:        "2024-12-09",
+			RelPath:     "2024-12-09.md",
+			FrontMatter: &vault.FrontMatter{Date: "2024-12-09", Closed: true},
+			Tags:        []string{"#daily"},
+			WordCount:   10,
+			Links:       []vault.Link{{Target: "Alpha", Wiki: true}},
+		},
+		{
+			Name:        "Alpha",
+			RelPath:     "projects/Alpha.md",
+			FrontMatter: &vault.FrontMatter{Date: "2024-11-20"},
+			Tags:        []string{"#project"},
+		},
+	})
+
+	content, err := Build(v, time.Date(2024, 12, 10, 12, 0, 0, 0, time.UTC), func(relPath string) string {
+		return "/notes/" + relPath
+	})
+	require.NoError(t, err)
+
+	html := string(content)
+	require.Contains(t, html, "<title>Дашборд</title>")
+	require.Contains(t, html, "Заметок: 2, закрыто: 1, открыто: 1")
+	require.Contains(t, html, `aria-label="Карта активности"`)
+	require.Contains(t, html, "2024-12-09: заметок 1, слов 10")
+	require.Contains(t, html, "#project, 2024-11: 1")
+	require.Contains(t, html, "Закрытые, 2024-12: 1")
+	require.Contains(t, html, `<a href="/notes/projects/Alpha.md"><text`)
+	require.NotContains(t, html, "<script")
+}
+
+func TestBuild_EmptyVault(t *testing.T) {
+	content, err := Build(vault.New("", nil), time.Now(), func(relPath string) string { return relPath })
+
+	require.NoError(t, err)
+	require.Equal(t, 4, strings.Count(string(content), "Нет данных"))
+}
+
+func TestHeatLevel(t *testing.T) {
+	testCases := []struct {
+		name     string
+		count    analytics.PeriodCount
+		maxWords int
+		expected int
+	}{
+		{name: "Нет заметок", count: analytics.PeriodCount{}, maxWords: 100, expected: 0},
+		{name: "Пустая заметка", count: analytics.PeriodCount{Notes: 1}, maxWords: 100, expected: 1},
+		{name: "Пустые заметки во всём хранилище", count: analytics.PeriodCount{Notes: 1}, maxWords: 0, expected: 1},
+		{name: "Половина максимума", count: analytics.PeriodCount{Notes: 1, Words: 50}, maxWords: 100, expected: 2},
+		{name: "Максимум", count: analytics.PeriodCount{Notes: 1, Words: 100}, maxWords: 100, expected: heatLevels},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, heatLevel(tc.count, tc.maxWords))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package dashboard
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/analytics"
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+const (
+	cellSize   = 11
+	cellGap    = 3
+	heatWeeks  = 53
+	heatLeft   = 28
+	heatTop    = 16
+	heatLevels = 4
+)
+
+// Цвета уровней активности как в GitHub: от «нет заметок» до максимума
+var heatColors = [heatLevels + 1]string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+var (
+	weekdayLabels = [7]string{"Пн", "", "Ср", "", "Пт", "", ""}
+	monthLabels   = [...]string{"янв", "фев", "мар", "апр", "май", "июн", "июл", "авг", "сен", "окт", "ноя", "дек"}
+)
+
+// heatmapSVG рисует календарь за год, заканчивающийся неделей последней заметки.
+// Яркость клетки зависит от количества слов, написанных в этот день
+func heatmapSVG(days []analytics.PeriodCount, lastDate string) string {
+	end, err := time.Parse(vault.DateLayout, lastDate)
+	if err != nil {
+		return ""
+	}
+
+	byDay := make(map[string]analytics.PeriodCount, len(days))
+	maxWords := 0
+	for _, d := range days {
+		byDay[d.Period] = d
+		if d.Words > maxWords {
+			maxWords = d.Words
+		}
+	}
+
+	// Недели начинаются с понедельника
+	offset := (int(end.Weekday()) + 6) % 7
+	weekEnd := end.AddDate(0, 0, 6-offset)
+	start := weekEnd.AddDate(0, 0, -heatWeeks*7+1)
+
+	width := heatLeft + heatWeeks*(cellSize+cellGap)
+	height := heatTop + 7*(cellSize+cellGap)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" role="img" aria-label="Карта активности">`, width, height)
+
+	for row, label := range weekdayLabels {
+		if label == "" {
+			continue
+		}
+		fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`, heatTop+row*(cellSize+cellGap)+cellSize-1, label)
+	}
+
+	for week := 0; week < heatWeeks; week++ {
+		x := heatLeft + week*(cellSize+cellGap)
+		for row := 0; row < 7; row++ {
+			day := start.AddDate(0, 0, week*7+row)
+			if day.After(end) {
+				continue
+			}
+			if day.Day() == 1 {
+				fmt.Fprintf(&b, `<text x="%d" y="10">%s</text>`, x, monthLabels[day.Month()-1])
+			}
+
+			key := day.Format(vault.DateLayout)
+			count := byDay[key]
+			fmt.Fprintf(&b,
+				`<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s</title></rect>`,
+				x, heatTop+row*(cellSize+cellGap), cellSize, cellSize,
+				heatColors[heatLevel(count, maxWords)],
+				html.EscapeString(fmt.Sprintf("%s: заметок %d, слов %d", key, count.Notes, count.Words)),
+			)
+		}
+	}
+
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+func heatLevel(count analytics.PeriodCount, maxWords int) int {
+	if count.Notes == 0 {
+		return 0
+	}
+	if maxWords == 0 {
+		return 1
+	}
+	level := int(math.Ceil(float64(heatLevels) * float64(count.Words) / float64(maxWords)))
+	return max(level, 1)
+}
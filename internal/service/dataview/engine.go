@@ -0,0 +1,231 @@
+package dataview
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+type Engine struct {
+	vault *vault.Vault
+	// href превращает относительный путь заметки в ссылку на её HTML-файл
+	href  func(relPath string) string
+	today time.Time
+}
+
+// NewEngine создаёт движок запросов. today — текущий момент в часовом поясе заметок:
+// в нём строятся date(today) и даты-литералы запросов
+func NewEngine(v *vault.Vault, href func(relPath string) string, today time.Time) *Engine {
+	return &Engine{vault: v, href: href, today: today}
+}
+
+// Result — строки результата запроса. Для TASK каждая строка — задача
+type Result struct {
+	Query   *Query
+	Headers []string
+	Rows    []Row
+}
+
+type Row struct {
+	Note   *vault.Note
+	Task   *vault.Task
+	Values []any
+}
+
+func (e *Engine) Execute(q *Query) *Result {
+	var rows []*row
+	for _, note := range e.vault.Notes {
+		if q.from != nil && !q.from.matches(e, note) {
+			continue
+		}
+		if q.Type == QueryTask {
+			for i := range note.Tasks {
+				rows = append(rows, &row{engine: e, note: note, task: &note.Tasks[i]})
+			}
+			continue
+		}
+		rows = append(rows, &row{engine: e, note: note})
+	}
+
+	filtered := rows[:0]
+	for _, r := range rows {
+		if q.where == nil || truthy(q.where.eval(r)) {
+			filtered = append(filtered, r)
+		}
+	}
+	rows = filtered
+
+	if len(q.sort) > 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			for _, key := range q.sort {
+				c := compare(key.expr.eval(rows[i]), key.expr.eval(rows[j]))
+				if c == 0 {
+					continue
+				}
+				if key.desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	if q.limit > 0 && len(rows) > q.limit {
+		rows = rows[:q.limit]
+	}
+
+	result := &Result{Query: q}
+	for _, c := range q.columns {
+		result.Headers = append(result.Headers, c.header)
+	}
+	for _, r := range rows {
+		out := Row{Note: r.note, Task: r.task}
+		for _, c := range q.columns {
+			out.Values = append(out.Values, c.expr.eval(r))
+		}
+		result.Rows = append(result.Rows, out)
+	}
+	return result
+}
+
+func (e *Engine) link(target string) linkValue {
+	return linkValue{
+		Target: target,
+		Note:   e.vault.Resolve(nil, vault.Link{Target: target, Wiki: true}),
+	}
+}
+
+// row — контекст вычисления выражений: заметка и, для TASK, задача
+type row struct {
+	engine *Engine
+	note   *vault.Note
+	task   *vault.Task
+}
+
+func (r *row) field(name string) any {
+	key := strings.ToLower(name)
+
+	if r.task != nil {
+		if v, ok := r.taskField(key); ok {
+			return v
+		}
+	}
+
+	note := r.note
+	switch key {
+	case "file.name":
+		return note.Name
+	case "file.path":
+		return filepath.ToSlash(note.RelPath)
+	case "file.folder":
+		folder := path.Dir(filepath.ToSlash(note.RelPath))
+		if folder == "." {
+			return ""
+		}
+		return folder
+	case "file.link":
+		return linkValue{Target: note.Name, Note: note}
+	case "file.tags", "file.etags":
+		return stringList(note.Tags)
+	case "file.day":
+		if date, ok := note.Date(); ok {
+			return date
+		}
+		return nil
+	case "file.outlinks":
+		var links []any
+		for _, link := range note.Links {
+			if target := r.engine.vault.Resolve(note, link); target != nil {
+				links = append(links, linkValue{Target: target.Name, Note: target})
+			}
+		}
+		return links
+	case "file.inlinks":
+		var links []any
+		for _, other := range r.engine.vault.Notes {
+			for _, link := range other.Links {
+				if r.engine.vault.Resolve(other, link) == note && other != note {
+					links = append(links, linkValue{Target: other.Name, Note: other})
+					break
+				}
+			}
+		}
+		return links
+	case "file.tasks":
+		var tasks []any
+		for _, task := range note.Tasks {
+			tasks = append(tasks, task.Text)
+		}
+		return tasks
+	case "file.words":
+		return float64(note.WordCount)
+	}
+
+	return r.metadataField(key)
+}
+
+func (r *row) taskField(key string) (any, bool) {
+	task := r.task
+	switch key {
+	case "text":
+		return task.Text, true
+	case "status":
+		return task.Status, true
+	case "completed":
+		return task.Status == vault.TaskDone, true
+	// fullyCompleted: ключи сравниваются в нижнем регистре
+	case "fullycompleted":
+		return !task.IsOpen(), true
+	case "due":
+		if task.Due == "" {
+			return nil, true
+		}
+		return toDate(task.Due, r.engine.today), true
+	case "completion":
+		if task.Completed == "" {
+			return nil, true
+		}
+		return toDate(task.Completed, r.engine.today), true
+	case "priority":
+		if task.Priority == "" {
+			return nil, true
+		}
+		return task.Priority, true
+	case "line":
+		return float64(task.Line), true
+	}
+	return nil, false
+}
+
+// metadataField ищет поле во FrontMatter и полях key:: value заметки
+func (r *row) metadataField(key string) any {
+	return r.engine.fromMetadata(r.note.Metadata[vault.FieldKey(key)])
+}
+
+// fromMetadata превращает ссылки из метаданных в ссылки на заметки хранилища
+func (e *Engine) fromMetadata(v any) any {
+	switch t := v.(type) {
+	case vault.Link:
+		return e.link(t.Target)
+	case []any:
+		list := make([]any, len(t))
+		for i, item := range t {
+			list[i] = e.fromMetadata(item)
+		}
+		return list
+	}
+	return v
+}
+
+func stringList(items []string) []any {
+	list := make([]any, len(items))
+	for i, item := range items {
+		list[i] = item
+	}
+	return list
+}
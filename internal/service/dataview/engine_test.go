@@ -0,0 +1,203 @@
+package dataview
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func testEngine() *Engine {
+	v := vault.New("", []*vault.Note{
+		{
+			Name:        "2024-12-08",
+			RelPath:     "daily/2024-12-08.md",
+			FrontMatter: &vault.FrontMatter{Date: "2024-12-08", Author: "ANkulagin", Closed: true, Tags: []string{"#daily"}},
+			Tags:        []string{"#daily"},
+			Links:       []vault.Link{{Target: "Alpha", Wiki: true}},
+			Tasks:       []vault.Task{{Text: "готово", Status: vault.TaskDone}},
+			Fields:      []vault.InlineField{{Key: "sleep", Value: 7 * time.Hour}, {Key: "project", Value: vault.Link{Target: "Alpha", Wiki: true}}},
+		},
+		{
+			Name:        "2024-12-09",
+			RelPath:     "daily/2024-12-09.md",
+			FrontMatter: &vault.FrontMatter{Date: "2024-12-09", Tags: []string{"#daily"}},
+			Tags:        []string{"#daily", "#project/alpha"},
+			Links:       []vault.Link{{Target: "Alpha", Wiki: true}},
+			Fields:      []vault.InlineField{{Key: "sleep", Value: 5*time.Hour + 30*time.Minute}},
+			Tasks: []vault.Task{
+				{Text: "позвонить", Status: vault.TaskOpen, Due: "2024-12-05"},
+				{Text: "написать", Status: vault.TaskOpen},
+			},
+		},
+		{
+			Name:        "Alpha",
+			RelPath:     "projects/Alpha.md",
+			FrontMatter: &vault.FrontMatter{Author: "Кто-то"},
+			Tags:        []string{"#project"},
+		},
+	})
+	return NewEngine(v, func(relPath string) string { return "/" + relPath }, time.Date(2024, 12, 10, 12, 0, 0, 0, time.UTC))
+}
+
+func names(result *Result) []string {
+	var out []string
+	for _, r := range result.Rows {
+		name := r.Note.Name
+		if r.Task != nil {
+			name += ":" + r.Task.Text
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+func TestEngine_Execute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{name: "FROM по тегу", src: "LIST FROM #daily", expected: []string{"2024-12-08", "2024-12-09"}},
+		{name: "Вложенный тег", src: "LIST FROM #project", expected: []string{"2024-12-09", "Alpha"}},
+		{name: "FROM по папке", src: `LIST FROM "projects"`, expected: []string{"Alpha"}},
+		{name: "Отрицание источника", src: `LIST FROM -"daily"`, expected: []string{"Alpha"}},
+		{name: "Входящие ссылки", src: "LIST FROM [[Alpha]]", expected: []string{"2024-12-08", "2024-12-09"}},
+		{name: "Исходящие ссылки", src: "LIST FROM outgoing([[2024-12-09]])", expected: []string{"Alpha"}},
+		{name: "WHERE по булеву полю", src: "LIST WHERE closed", expected: []string{"2024-12-08"}},
+		{name: "Сравнение дат", src: "LIST WHERE date < date(2024-12-09)", expected: []string{"2024-12-08"}},
+		{name: "Дата относительно сегодня", src: "LIST WHERE date = date(yesterday)", expected: []string{"2024-12-09"}},
+		{name: "contains по строке", src: `LIST WHERE contains(author, "ank")`, expected: []string{"2024-12-08"}},
+		{name: "SORT по убыванию и LIMIT", src: "LIST SORT file.name DESC LIMIT 2", expected: []string{"Alpha", "2024-12-09"}},
+		{name: "Пустые значения в конце при сортировке по убыванию", src: "LIST SORT date DESC", expected: []string{"2024-12-09", "2024-12-08", "Alpha"}},
+		{name: "Сортировка по длительности", src: "LIST WHERE sleep SORT sleep", expected: []string{"2024-12-09", "2024-12-08"}},
+		{name: "Сравнение со ссылкой из поля", src: "LIST WHERE project = [[Alpha]]", expected: []string{"2024-12-08"}},
+		{name: "TASK с фильтром по статусу", src: "TASK WHERE !completed", expected: []string{"2024-12-09:позвонить", "2024-12-09:написать"}},
+		{name: "fullyCompleted в записи Dataview", src: "TASK WHERE !fullyCompleted", expected: []string{"2024-12-09:позвонить", "2024-12-09:написать"}},
+		{name: "Просроченные задачи", src: "TASK WHERE due < date(today)", expected: []string{"2024-12-09:позвонить"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := testEngine()
+			q, err := Parse(tc.src)
+			require.NoError(t, err)
+
+			result := sut.Execute(q)
+
+			require.Equal(t, tc.expected, names(result))
+		})
+	}
+}
+
+func TestEngine_Execute_Location(t *testing.T) {
+	root := t.TempDir()
+	moscow := time.FixedZone("MSK", 3*60*60)
+	files := map[string]string{
+		"2024-12-09.md": "---\ndate: 2024-12-09\n---\nreview:: 2024-12-09\n",
+		"2024-12-08.md": "---\ndate: 2024-12-08\n---\nreview:: 2024-12-08\n",
+	}
+	var notes []*vault.Note
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		note, err := vault.ParseNote(path, root, vault.WithLocation(moscow))
+		require.NoError(t, err)
+		notes = append(notes, note)
+	}
+	// В Москве уже 9 декабря, в UTC ещё 8-е
+	sut := NewEngine(vault.New(root, notes), func(relPath string) string { return relPath }, time.Date(2024, 12, 9, 1, 0, 0, 0, moscow))
+
+	testCases := []struct {
+		src      string
+		expected []string
+	}{
+		{src: "LIST WHERE file.day = date(today)", expected: []string{"2024-12-09"}},
+		{src: "LIST WHERE file.day >= date(2024-12-09)", expected: []string{"2024-12-09"}},
+		{src: "LIST WHERE review = date(2024-12-09)", expected: []string{"2024-12-09"}},
+		{src: `LIST WHERE date = "2024-12-08"`, expected: []string{"2024-12-08"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.src, func(t *testing.T) {
+			q, err := Parse(tc.src)
+			require.NoError(t, err)
+
+			result := sut.Execute(q)
+
+			require.Equal(t, tc.expected, names(result))
+		})
+	}
+}
+
+func TestEngine_RenderBlock(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name: "LIST со значением",
+			src:  "LIST author FROM \"projects\"",
+			expected: []string{
+				`<ul class="dataview dataview-list">`,
+				`<li><a class="internal-link" href="/projects/Alpha.md">Alpha</a>: Кто-то</li>`,
+			},
+		},
+		{
+			name: "TABLE со ссылками и списками",
+			src:  "TABLE file.tags AS Теги, file.outlinks FROM #daily SORT file.name LIMIT 1",
+			expected: []string{
+				`<th>Файл</th><th>Теги</th><th>file.outlinks</th>`,
+				`<td>#daily</td><td><a class="internal-link" href="/projects/Alpha.md">Alpha</a></td>`,
+			},
+		},
+		{
+			name: "TABLE WITHOUT ID и пустые значения",
+			src:  "TABLE WITHOUT ID author FROM #daily SORT file.name DESC LIMIT 1",
+			expected: []string{
+				`<thead><tr><th>author</th></tr></thead>`,
+				`<tr><td>-</td></tr>`,
+			},
+		},
+		{
+			name: "TASK сгруппированы по заметкам",
+			src:  "TASK",
+			expected: []string{
+				`<li><a class="internal-link" href="/daily/2024-12-08.md">2024-12-08</a><ul class="task-list">`,
+				`<input type="checkbox" disabled checked> готово`,
+				`<input type="checkbox" disabled> позвонить <span class="task-due">📅 2024-12-05</span>`,
+			},
+		},
+		{
+			name: "Значения полей из текста",
+			src:  "TABLE sleep, project FROM #daily SORT file.name LIMIT 1",
+			expected: []string{
+				`<td>7h</td><td><a class="internal-link" href="/projects/Alpha.md">Alpha</a></td>`,
+			},
+		},
+		{
+			name:     "Пустой результат",
+			src:      "LIST FROM #nothing",
+			expected: []string{"Нет результатов"},
+		},
+		{
+			name:     "Ошибка запроса",
+			src:      "LIST WHERE <",
+			expected: []string{`<div class="dataview dataview-error">Ошибка запроса Dataview: неожиданное &#34;&lt;&#34;`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			html := testEngine().RenderBlock(tc.src)
+
+			for _, expected := range tc.expected {
+				require.Contains(t, html, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package dataview
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+// Значения выражений: nil, float64, string, bool, time.Time, linkValue, []any
+
+type linkValue struct {
+	Target string
+	Note   *vault.Note
+}
+
+type expr interface {
+	eval(r *row) any
+}
+
+type literal struct {
+	value any
+}
+
+func (l literal) eval(*row) any {
+	return l.value
+}
+
+type field struct {
+	name string
+}
+
+func (f field) eval(r *row) any {
+	return r.field(f.name)
+}
+
+type linkLiteral struct {
+	target string
+}
+
+func (l linkLiteral) eval(r *row) any {
+	return r.engine.link(l.target)
+}
+
+type unary struct {
+	op      string
+	operand expr
+}
+
+func (u unary) eval(r *row) any {
+	v := u.operand.eval(r)
+	switch u.op {
+	case "!":
+		return !truthy(v)
+	case "-":
+		if n, ok := v.(float64); ok {
+			return -n
+		}
+	}
+	return nil
+}
+
+type binary struct {
+	op          string
+	left, right expr
+}
+
+func (b binary) eval(r *row) any {
+	switch b.op {
+	case "and":
+		return truthy(b.left.eval(r)) && truthy(b.right.eval(r))
+	case "or":
+		return truthy(b.left.eval(r)) || truthy(b.right.eval(r))
+	}
+
+	left, right := b.left.eval(r), b.right.eval(r)
+	switch b.op {
+	case "=":
+		return compare(left, right) == 0
+	case "!=":
+		return compare(left, right) != 0
+	case "<":
+		return left != nil && right != nil && compare(left, right) < 0
+	case ">":
+		return left != nil && right != nil && compare(left, right) > 0
+	case "<=":
+		return left != nil && right != nil && compare(left, right) <= 0
+	case ">=":
+		return left != nil && right != nil && compare(left, right) >= 0
+	}
+	return arithmetic(b.op, left, right)
+}
+
+type call struct {
+	name string
+	args []expr
+}
+
+func (c call) eval(r *row) any {
+	args := make([]any, len(c.args))
+	for i, a := range c.args {
+		args[i] = a.eval(r)
+	}
+
+	switch strings.ToLower(c.name) {
+	case "contains":
+		if len(args) != 2 {
+			return nil
+		}
+		return contains(args[0], args[1])
+	case "date":
+		if len(args) != 1 {
+			return nil
+		}
+		return toDate(args[0], r.engine.today)
+	case "length":
+		if len(args) != 1 {
+			return nil
+		}
+		switch v := args[0].(type) {
+		case []any:
+			return float64(len(v))
+		case string:
+			return float64(len([]rune(v)))
+		}
+		return float64(0)
+	case "lower":
+		if len(args) == 1 {
+			return strings.ToLower(formatPlain(args[0]))
+		}
+	case "upper":
+		if len(args) == 1 {
+			return strings.ToUpper(formatPlain(args[0]))
+		}
+	case "default":
+		if len(args) == 2 {
+			if args[0] == nil {
+				return args[1]
+			}
+			return args[0]
+		}
+	}
+	return nil
+}
+
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case float64:
+		return t != 0
+	case string:
+		return t != ""
+	case []any:
+		return len(t) > 0
+	}
+	return true
+}
+
+// compare упорядочивает значения; nil считается меньше любого значения
+func compare(a, b any) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	switch av := a.(type) {
+	case float64:
+		if bv, ok := b.(float64); ok {
+			return cmpOrdered(av, bv)
+		}
+	case bool:
+		if bv, ok := b.(bool); ok {
+			return cmpOrdered(boolInt(av), boolInt(bv))
+		}
+	case time.Time:
+		// Строка-дата разбирается в часовом поясе сравниваемой даты
+		if bv, ok := toDate(b, time.Time{}.In(av.Location())).(time.Time); ok {
+			return av.Compare(bv)
+		}
+	case time.Duration:
+		if bv, ok := b.(time.Duration); ok {
+			return cmpOrdered(float64(av), float64(bv))
+		}
+	case linkValue:
+		if bv, ok := b.(linkValue); ok {
+			return cmpOrdered(strings.ToLower(av.Target), strings.ToLower(bv.Target))
+		}
+	}
+
+	if bt, ok := b.(time.Time); ok {
+		return -compare(bt, a)
+	}
+
+	return cmpOrdered(strings.ToLower(formatPlain(a)), strings.ToLower(formatPlain(b)))
+}
+
+func cmpOrdered[T int | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func arithmetic(op string, left, right any) any {
+	if l, ok := left.(float64); ok {
+		if r, ok := right.(float64); ok {
+			switch op {
+			case "+":
+				return l + r
+			case "-":
+				return l - r
+			case "*":
+				return l * r
+			case "/":
+				if r == 0 {
+					return nil
+				}
+				return l / r
+			}
+		}
+	}
+	if op == "+" && left != nil && right != nil {
+		return formatPlain(left) + formatPlain(right)
+	}
+	return nil
+}
+
+func contains(haystack, needle any) bool {
+	switch h := haystack.(type) {
+	case []any:
+		for _, item := range h {
+			if compare(item, needle) == 0 {
+				return true
+			}
+			// contains(file.tags, "#project") находит и вложенные теги #project/alpha
+			if s, ok := item.(string); ok && strings.HasPrefix(s, "#") {
+				if n, ok := needle.(string); ok && strings.HasPrefix(strings.ToLower(s), strings.ToLower(n)+"/") {
+					return true
+				}
+			}
+		}
+		return false
+	case string:
+		return strings.Contains(strings.ToLower(h), strings.ToLower(formatPlain(needle)))
+	case linkValue:
+		return compare(h, needle) == 0
+	}
+	return false
+}
+
+// toDate приводит значение к дате; понимает today, now, yesterday, tomorrow и YYYY-MM-DD.
+// Даты строятся в часовом поясе today — том же, в котором разобраны даты заметок
+func toDate(v any, today time.Time) any {
+	loc := today.Location()
+	switch t := v.(type) {
+	case time.Time:
+		return t
+	case string:
+		day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+		switch strings.ToLower(t) {
+		case "today", "now":
+			return day
+		case "yesterday":
+			return day.AddDate(0, 0, -1)
+		case "tomorrow":
+			return day.AddDate(0, 0, 1)
+		}
+		if len(t) >= len(vault.DateLayout) {
+			if parsed, err := time.ParseInLocation(vault.DateLayout, t[:len(vault.DateLayout)], loc); err == nil {
+				return parsed
+			}
+		}
+	case linkValue:
+		if t.Note != nil {
+			if date, ok := t.Note.Date(); ok {
+				return date
+			}
+		}
+	}
+	return nil
+}
+
+// formatPlain превращает значение в текст без разметки
+func formatPlain(v any) string {
+	switch t := v.(type) {
+	case linkValue:
+		if t.Note != nil {
+			return t.Note.Name
+		}
+		return t.Target
+	case []any:
+		parts := make([]string, len(t))
+		for i, item := range t {
+			parts[i] = formatPlain(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return vault.FormatValue(v)
+}
@@ -0,0 +1,152 @@
+package dataview
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokDate
+	tokTag
+	tokLink
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// Позиции начала и конца токена в исходном запросе (в рунах)
+	start, end int
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && strings.EqualFold(t.text, text)
+}
+
+func (t token) isKeyword(words ...string) bool {
+	if t.kind != tokIdent {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Двухсимвольные операторы проверяются раньше односимвольных
+var operators = []string{"!=", "<=", ">=", "&&", "||", "=", "<", ">", "!", "+", "-", "*", "/", "(", ")", ",", "[", "]", "&", "|"}
+
+func tokenize(src string) ([]token, error) {
+	runes := []rune(src)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+
+		case r == '"':
+			var b strings.Builder
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("незакрытая строка в позиции %d", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokString, text: b.String(), start: start, end: i})
+
+		case r == '[' && i+1 < len(runes) && runes[i+1] == '[':
+			end := strings.Index(string(runes[i:]), "]]")
+			if end < 0 {
+				return nil, fmt.Errorf("незакрытая ссылка в позиции %d", start)
+			}
+			inner := []rune(string(runes[i:])[2:end])
+			i += len(inner) + 4
+			tokens = append(tokens, token{kind: tokLink, text: string(inner), start: start, end: i})
+
+		case r == '#' && i+1 < len(runes) && isIdentRune(runes[i+1]):
+			i++
+			for i < len(runes) && (isIdentRune(runes[i]) || runes[i] == '/' || runes[i] == '-') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokTag, text: string(runes[start:i]), start: start, end: i})
+
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			// Дата вида 2024-12-09 записывается без кавычек
+			if i-start == 4 && isDateAt(runes, start) {
+				i = start + 10
+				tokens = append(tokens, token{kind: tokDate, text: string(runes[start:i]), start: start, end: i})
+				continue
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: string(runes[start:i]), start: start, end: i})
+
+		case isIdentRune(r):
+			for i < len(runes) && (isIdentRune(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: string(runes[start:i]), start: start, end: i})
+
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					i += len([]rune(op))
+					tokens = append(tokens, token{kind: tokOp, text: op, start: start, end: i})
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("неожиданный символ %q в позиции %d", r, start)
+			}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, start: len(runes), end: len(runes)})
+	return tokens, nil
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isDateAt(runes []rune, i int) bool {
+	if i+10 > len(runes) {
+		return false
+	}
+	for k, r := range runes[i : i+10] {
+		switch k {
+		case 4, 7:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !unicode.IsDigit(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,360 @@
+package dataview
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	QueryList  = "LIST"
+	QueryTable = "TABLE"
+	QueryTask  = "TASK"
+)
+
+type column struct {
+	header string
+	expr   expr
+}
+
+type sortKey struct {
+	expr expr
+	desc bool
+}
+
+type Query struct {
+	Type      string
+	WithoutID bool
+	columns   []column
+	from      source
+	where     expr
+	sort      []sortKey
+	limit     int
+}
+
+type parser struct {
+	src    []rune
+	tokens []token
+	pos    int
+}
+
+// Parse разбирает запрос в подмножестве языка Dataview:
+// LIST|TABLE [WITHOUT ID]|TASK [поля] [FROM источник] [WHERE условие] [SORT поле ASC|DESC, ...] [LIMIT n]
+func Parse(src string) (*Query, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: []rune(src), tokens: tokens}
+
+	q := &Query{}
+	head := p.next()
+	switch {
+	case head.isKeyword(QueryList, QueryTable, QueryTask):
+		q.Type = strings.ToUpper(head.text)
+	default:
+		return nil, fmt.Errorf("запрос должен начинаться с LIST, TABLE или TASK, а не %q", head.text)
+	}
+
+	if p.peek().isKeyword("WITHOUT") {
+		p.next()
+		if !p.next().isKeyword("ID") {
+			return nil, fmt.Errorf("ожидалось WITHOUT ID")
+		}
+		q.WithoutID = true
+	}
+
+	if q.Type != QueryTask && !p.atClause() {
+		if err := p.parseColumns(q); err != nil {
+			return nil, err
+		}
+	}
+
+	for p.peek().kind != tokEOF {
+		clause := p.next()
+		switch {
+		case clause.isKeyword("FROM"):
+			q.from, err = p.parseSource()
+		case clause.isKeyword("WHERE"):
+			var where expr
+			where, err = p.parseExpr()
+			if q.where != nil && err == nil {
+				where = binary{op: "and", left: q.where, right: where}
+			}
+			q.where = where
+		case clause.isKeyword("SORT"):
+			err = p.parseSort(q)
+		case clause.isKeyword("LIMIT"):
+			n := p.next()
+			if n.kind != tokNumber {
+				return nil, fmt.Errorf("LIMIT ожидает число, а не %q", n.text)
+			}
+			q.limit, err = strconv.Atoi(n.text)
+		default:
+			return nil, fmt.Errorf("неожиданное %q в позиции %d", clause.text, clause.start)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) atClause() bool {
+	t := p.peek()
+	return t.kind == tokEOF || t.isKeyword("FROM", "WHERE", "SORT", "LIMIT")
+}
+
+func (p *parser) expect(op string) error {
+	t := p.next()
+	if !t.is(tokOp, op) {
+		return fmt.Errorf("ожидалось %q, а найдено %q в позиции %d", op, t.text, t.start)
+	}
+	return nil
+}
+
+func (p *parser) parseColumns(q *Query) error {
+	for {
+		start := p.peek().start
+		e, err := p.parseExpr()
+		if err != nil {
+			return err
+		}
+		header := strings.TrimSpace(string(p.src[start:p.tokens[p.pos-1].end]))
+
+		if p.peek().isKeyword("AS") {
+			p.next()
+			name := p.next()
+			if name.kind != tokString && name.kind != tokIdent {
+				return fmt.Errorf("после AS ожидается имя столбца")
+			}
+			header = name.text
+		}
+		q.columns = append(q.columns, column{header: header, expr: e})
+
+		if !p.peek().is(tokOp, ",") {
+			return nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseSort(q *Query) error {
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return err
+		}
+		key := sortKey{expr: e}
+		if p.peek().isKeyword("ASC", "ASCENDING") {
+			p.next()
+		} else if p.peek().isKeyword("DESC", "DESCENDING") {
+			p.next()
+			key.desc = true
+		}
+		q.sort = append(q.sort, key)
+
+		if !p.peek().is(tokOp, ",") {
+			return nil
+		}
+		p.next()
+	}
+}
+
+// Выражения: or → and → сравнение → сложение → умножение → унарные → первичные
+
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("or") || p.peek().is(tokOp, "||") || p.peek().is(tokOp, "|") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("and") || p.peek().is(tokOp, "&&") || p.peek().is(tokOp, "&") {
+		p.next()
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind == tokOp {
+		switch t.text {
+		case "=", "!=", "<", ">", "<=", ">=":
+			p.next()
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return binary{op: t.text, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(tokOp, "+") || p.peek().is(tokOp, "-") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(tokOp, "*") || p.peek().is(tokOp, "/") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.peek().is(tokOp, "!") || p.peek().is(tokOp, "-") {
+		op := p.next().text
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: op, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("некорректное число %q", t.text)
+		}
+		return literal{value: n}, nil
+	case tokString, tokTag:
+		return literal{value: t.text}, nil
+	case tokDate:
+		return call{name: "date", args: []expr{literal{value: t.text}}}, nil
+	case tokLink:
+		return linkLiteral{target: linkTarget(t.text)}, nil
+	case tokIdent:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		case "null":
+			return literal{value: nil}, nil
+		}
+		if p.peek().is(tokOp, "(") {
+			p.next()
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			// date(today) — аргумент-слово трактуется как строка
+			if strings.EqualFold(t.text, "date") && len(args) == 1 {
+				if f, ok := args[0].(field); ok {
+					args[0] = literal{value: f.name}
+				}
+			}
+			return call{name: t.text, args: args}, nil
+		}
+		return field{name: t.text}, nil
+	case tokOp:
+		if t.text == "(" {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return e, p.expect(")")
+		}
+	}
+	if t.kind == tokEOF {
+		return nil, fmt.Errorf("неожиданный конец запроса")
+	}
+	return nil, fmt.Errorf("неожиданное %q в позиции %d", t.text, t.start)
+}
+
+func (p *parser) parseArgs() ([]expr, error) {
+	var args []expr
+	if p.peek().is(tokOp, ")") {
+		p.next()
+		return args, nil
+	}
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, e)
+		if p.peek().is(tokOp, ")") {
+			p.next()
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// linkTarget убирает из ссылки [[Note#Heading|Текст]] всё, кроме имени заметки
+func linkTarget(text string) string {
+	text, _, _ = strings.Cut(text, "|")
+	text, _, _ = strings.Cut(text, "#")
+	return strings.TrimSpace(text)
+}
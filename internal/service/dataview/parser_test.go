@@ -0,0 +1,95 @@
+package dataview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Success(t *testing.T) {
+	testCases := []struct {
+		name  string
+		src   string
+		check func(t *testing.T, q *Query)
+	}{
+		{
+			name: "LIST без полей",
+			src:  "LIST FROM #daily",
+			check: func(t *testing.T, q *Query) {
+				require.Equal(t, QueryList, q.Type)
+				require.Empty(t, q.columns)
+				require.Equal(t, tagSource{tag: "#daily"}, q.from)
+			},
+		},
+		{
+			name: "TABLE с псевдонимами и всеми секциями",
+			src: `table without id file.link AS "Заметка", author, length(file.tags) as Теги
+FROM "daily" and -#draft
+WHERE closed = false AND date >= 2024-12-01
+SORT date DESC, file.name
+LIMIT 5`,
+			check: func(t *testing.T, q *Query) {
+				require.Equal(t, QueryTable, q.Type)
+				require.True(t, q.WithoutID)
+				require.Len(t, q.columns, 3)
+				require.Equal(t, "Заметка", q.columns[0].header)
+				require.Equal(t, "author", q.columns[1].header)
+				require.Equal(t, "Теги", q.columns[2].header)
+				require.NotNil(t, q.where)
+				require.Len(t, q.sort, 2)
+				require.True(t, q.sort[0].desc)
+				require.False(t, q.sort[1].desc)
+				require.Equal(t, 5, q.limit)
+			},
+		},
+		{
+			name: "TASK с условием",
+			src:  "TASK FROM [[Проект]] WHERE !completed",
+			check: func(t *testing.T, q *Query) {
+				require.Equal(t, QueryTask, q.Type)
+				require.Equal(t, incomingSource{target: "Проект"}, q.from)
+				require.NotNil(t, q.where)
+			},
+		},
+		{
+			name: "Заголовок столбца берётся из текста выражения",
+			src:  "TABLE date(today) - 1",
+			check: func(t *testing.T, q *Query) {
+				require.Equal(t, "date(today) - 1", q.columns[0].header)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := Parse(tc.src)
+
+			require.NoError(t, err)
+			tc.check(t, q)
+		})
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	testCases := []struct {
+		name           string
+		src            string
+		expectedErrMsg string
+	}{
+		{name: "Неизвестный тип", src: "CALENDAR file.day", expectedErrMsg: "запрос должен начинаться с LIST, TABLE или TASK"},
+		{name: "Незакрытая строка", src: `LIST FROM "daily`, expectedErrMsg: "незакрытая строка"},
+		{name: "Некорректный LIMIT", src: "LIST LIMIT много", expectedErrMsg: "LIMIT ожидает число"},
+		{name: "Незакрытая скобка", src: "LIST WHERE (closed", expectedErrMsg: "ожидалось \")\""},
+		{name: "Некорректный источник", src: "LIST FROM 42", expectedErrMsg: "некорректный источник FROM"},
+		{name: "Лишний текст", src: "LIST file.name extra", expectedErrMsg: "неожиданное \"extra\""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.src)
+
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedErrMsg)
+		})
+	}
+}
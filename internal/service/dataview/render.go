@@ -0,0 +1,131 @@
+package dataview
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	log "github.com/sirupsen/logrus"
+)
+
+// RenderBlock выполняет запрос из блока ```dataview и возвращает статический HTML.
+// Ошибка разбора не прерывает конвертацию, а показывается на месте блока
+func (e *Engine) RenderBlock(src string) string {
+	q, err := Parse(src)
+	if err != nil {
+		log.Warnf("Ошибка в запросе Dataview: %v", err)
+		return fmt.Sprintf("<div class=\"dataview dataview-error\">Ошибка запроса Dataview: %s</div>\n", html.EscapeString(err.Error()))
+	}
+	return e.Render(e.Execute(q))
+}
+
+func (e *Engine) Render(result *Result) string {
+	if len(result.Rows) == 0 {
+		return "<div class=\"dataview dataview-empty\">Нет результатов</div>\n"
+	}
+
+	switch result.Query.Type {
+	case QueryTable:
+		return e.renderTable(result)
+	case QueryTask:
+		return e.renderTasks(result)
+	default:
+		return e.renderList(result)
+	}
+}
+
+func (e *Engine) renderList(result *Result) string {
+	var b strings.Builder
+	b.WriteString("<ul class=\"dataview dataview-list\">\n")
+	for _, r := range result.Rows {
+		b.WriteString("<li>")
+		if !result.Query.WithoutID {
+			b.WriteString(e.noteLink(r.Note))
+			if len(r.Values) > 0 {
+				b.WriteString(": ")
+			}
+		}
+		if len(r.Values) > 0 {
+			b.WriteString(e.formatValue(r.Values[0]))
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul>\n")
+	return b.String()
+}
+
+func (e *Engine) renderTable(result *Result) string {
+	var b strings.Builder
+	b.WriteString("<table class=\"dataview dataview-table\">\n<thead><tr>")
+	if !result.Query.WithoutID {
+		b.WriteString("<th>Файл</th>")
+	}
+	for _, h := range result.Headers {
+		fmt.Fprintf(&b, "<th>%s</th>", html.EscapeString(h))
+	}
+	b.WriteString("</tr></thead>\n<tbody>\n")
+	for _, r := range result.Rows {
+		b.WriteString("<tr>")
+		if !result.Query.WithoutID {
+			fmt.Fprintf(&b, "<td>%s</td>", e.noteLink(r.Note))
+		}
+		for _, v := range r.Values {
+			fmt.Fprintf(&b, "<td>%s</td>", e.formatValue(v))
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return b.String()
+}
+
+// renderTasks группирует задачи по заметкам, как это делает Dataview
+func (e *Engine) renderTasks(result *Result) string {
+	var b strings.Builder
+	b.WriteString("<ul class=\"dataview dataview-tasks\">\n")
+	var current *vault.Note
+	for _, r := range result.Rows {
+		if r.Note != current {
+			if current != nil {
+				b.WriteString("</ul></li>\n")
+			}
+			current = r.Note
+			fmt.Fprintf(&b, "<li>%s<ul class=\"task-list\">\n", e.noteLink(r.Note))
+		}
+		checked := ""
+		if !r.Task.IsOpen() {
+			checked = " checked"
+		}
+		fmt.Fprintf(&b, "<li class=\"task-list-item\"><input type=\"checkbox\" disabled%s> %s", checked, html.EscapeString(r.Task.Text))
+		if r.Task.Due != "" {
+			fmt.Fprintf(&b, " <span class=\"task-due\">📅 %s</span>", r.Task.Due)
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul></li>\n</ul>\n")
+	return b.String()
+}
+
+func (e *Engine) noteLink(note *vault.Note) string {
+	return fmt.Sprintf("<a class=\"internal-link\" href=\"%s\">%s</a>",
+		html.EscapeString(e.href(note.RelPath)), html.EscapeString(note.Name))
+}
+
+func (e *Engine) formatValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "-"
+	case linkValue:
+		if t.Note != nil {
+			return e.noteLink(t.Note)
+		}
+		return html.EscapeString(t.Target)
+	case []any:
+		parts := make([]string, len(t))
+		for i, item := range t {
+			parts[i] = e.formatValue(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return html.EscapeString(formatPlain(v))
+}
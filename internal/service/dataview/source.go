@@ -0,0 +1,162 @@
+package dataview
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+// source отбирает заметки для FROM: #тег, "папка", [[ссылка]], outgoing([[ссылка]]) и их комбинации
+type source interface {
+	matches(e *Engine, note *vault.Note) bool
+}
+
+type tagSource struct {
+	tag string
+}
+
+func (s tagSource) matches(_ *Engine, note *vault.Note) bool {
+	for _, tag := range note.Tags {
+		if strings.EqualFold(tag, s.tag) || strings.HasPrefix(strings.ToLower(tag), strings.ToLower(s.tag)+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type folderSource struct {
+	folder string
+}
+
+func (s folderSource) matches(_ *Engine, note *vault.Note) bool {
+	folder := strings.Trim(filepath.ToSlash(s.folder), "/")
+	relPath := filepath.ToSlash(note.RelPath)
+	if strings.HasSuffix(strings.ToLower(folder), ".md") {
+		return relPath == folder
+	}
+	return folder == "" || strings.HasPrefix(relPath, folder+"/")
+}
+
+// incomingSource — заметки, ссылающиеся на target
+type incomingSource struct {
+	target string
+}
+
+func (s incomingSource) matches(e *Engine, note *vault.Note) bool {
+	target := e.link(s.target).Note
+	if target == nil {
+		return false
+	}
+	for _, link := range note.Links {
+		if e.vault.Resolve(note, link) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// outgoingSource — заметки, на которые ссылается target
+type outgoingSource struct {
+	target string
+}
+
+func (s outgoingSource) matches(e *Engine, note *vault.Note) bool {
+	from := e.link(s.target).Note
+	if from == nil {
+		return false
+	}
+	for _, link := range from.Links {
+		if e.vault.Resolve(from, link) == note {
+			return true
+		}
+	}
+	return false
+}
+
+type notSource struct {
+	inner source
+}
+
+func (s notSource) matches(e *Engine, note *vault.Note) bool {
+	return !s.inner.matches(e, note)
+}
+
+type logicSource struct {
+	and         bool
+	left, right source
+}
+
+func (s logicSource) matches(e *Engine, note *vault.Note) bool {
+	if s.and {
+		return s.left.matches(e, note) && s.right.matches(e, note)
+	}
+	return s.left.matches(e, note) || s.right.matches(e, note)
+}
+
+func (p *parser) parseSource() (source, error) {
+	left, err := p.parseSourceAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("or") || p.peek().is(tokOp, "|") || p.peek().is(tokOp, "||") {
+		p.next()
+		right, err := p.parseSourceAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicSource{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseSourceAnd() (source, error) {
+	left, err := p.parseSourceUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("and") || p.peek().is(tokOp, "&") || p.peek().is(tokOp, "&&") {
+		p.next()
+		right, err := p.parseSourceUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicSource{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseSourceUnary() (source, error) {
+	t := p.next()
+	switch {
+	case t.is(tokOp, "-"), t.is(tokOp, "!"):
+		inner, err := p.parseSourceUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notSource{inner: inner}, nil
+	case t.is(tokOp, "("):
+		inner, err := p.parseSource()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case t.kind == tokTag:
+		return tagSource{tag: t.text}, nil
+	case t.kind == tokString:
+		return folderSource{folder: t.text}, nil
+	case t.kind == tokLink:
+		return incomingSource{target: linkTarget(t.text)}, nil
+	case t.isKeyword("outgoing"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		link := p.next()
+		if link.kind != tokLink {
+			return nil, fmt.Errorf("outgoing ожидает ссылку [[...]]")
+		}
+		return outgoingSource{target: linkTarget(link.text)}, p.expect(")")
+	}
+	return nil, fmt.Errorf("некорректный источник FROM: %q", t.text)
+}
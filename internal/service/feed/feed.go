@@ -0,0 +1,58 @@
+package feed
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	RSSFileName  = "feed.xml"
+	AtomFileName = "atom.xml"
+	JSONFileName = "feed.json"
+)
+
+var (
+	htmlTagRe    = regexp.MustCompile(`<[^>]*>`)
+	whitespaceRe = regexp.MustCompile(`\s+`)
+)
+
+type Feed struct {
+	Title       string
+	Description string
+	// Адрес сайта с заметками
+	HomeURL string
+	Author  string
+	Updated time.Time
+	Items   []Item
+}
+
+type Item struct {
+	// Абсолютный адрес страницы заметки; он же постоянный идентификатор записи
+	URL    string
+	Title  string
+	Author string
+	Date   time.Time
+	// Теги без символа #
+	Tags []string
+	// Полный HTML заметки; пусто — в ленту попадает только Summary
+	Content string
+	Summary string
+}
+
+// Summary возвращает начало текста HTML длиной не больше limit символов, обрезанное по слову
+func Summary(htmlContent string, limit int) string {
+	text := html.UnescapeString(htmlTagRe.ReplaceAllString(htmlContent, " "))
+	text = strings.TrimSpace(whitespaceRe.ReplaceAllString(text, " "))
+
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	cut := string(runes[:limit])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:—-") + "…"
+}
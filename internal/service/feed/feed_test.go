@@ -0,0 +1,67 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testFeed() *Feed {
+	date := time.Date(2024, 12, 9, 21, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	return &Feed{
+		Title:   "Дневник",
+		HomeURL: "https://example.com/",
+		Author:  "Команда",
+		Updated: date,
+		Items: []Item{
+			{URL: "https://example.com/a.html", Title: "A & B", Author: "Иван", Date: date, Tags: []string{"daily"}, Content: "<p>Текст</p>", Summary: "Текст"},
+			{URL: "https://example.com/b.html", Title: "B", Date: date, Summary: "Кратко"},
+		},
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteRSS(&buf, testFeed(), "https://example.com/feed.xml"))
+
+	out := buf.String()
+	require.Contains(t, out, `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:dc="http://purl.org/dc/elements/1.1/">`)
+	require.Contains(t, out, "<title>A &amp; B</title>")
+	require.Contains(t, out, `<guid isPermaLink="true">https://example.com/a.html</guid>`)
+	require.Contains(t, out, "<pubDate>Mon, 09 Dec 2024 21:00:00 +0300</pubDate>")
+	require.Contains(t, out, "<description>&lt;p&gt;Текст&lt;/p&gt;</description>")
+	require.Contains(t, out, "<description>Кратко</description>")
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), new(struct{})))
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteAtom(&buf, testFeed(), "https://example.com/atom.xml"))
+
+	out := buf.String()
+	require.Contains(t, out, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	require.Contains(t, out, "<id>https://example.com/atom.xml</id>")
+	require.Contains(t, out, "<updated>2024-12-09T21:00:00+03:00</updated>")
+	require.Contains(t, out, "<author>\n      <name>Иван</name>\n    </author>")
+	require.Contains(t, out, `<content type="html">&lt;p&gt;Текст&lt;/p&gt;</content>`)
+	require.Contains(t, out, `<summary type="text">Кратко</summary>`)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, testFeed(), "https://example.com/feed.json"))
+
+	out := buf.String()
+	require.Contains(t, out, `"version": "https://jsonfeed.org/version/1.1"`)
+	require.Contains(t, out, `"content_html": "<p>Текст</p>"`)
+	require.Contains(t, out, `"content_text": "Кратко"`)
+	require.Contains(t, out, `"date_published": "2024-12-09T21:00:00+03:00"`)
+}
+
+func TestSummary(t *testing.T) {
+	require.Equal(t, "Заголовок Текст & ещё", Summary("<h1>Заголовок</h1>\n<p>Текст &amp; ещё</p>", 100))
+	require.Equal(t, "Первое второе…", Summary("<p>Первое второе, третье</p>", 16))
+}
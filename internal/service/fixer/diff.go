@@ -0,0 +1,136 @@
+package fixer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Строк контекста вокруг изменений, как у diff -u
+const diffContext = 3
+
+type diffLine struct {
+	op   byte
+	text string
+	// Число строк старого и нового текста перед этой строкой
+	before, after int
+}
+
+// Diff возвращает изменения файла name в формате unified diff; пустая строка — изменений нет
+func Diff(name string, before, after []byte) string {
+	lines := diffLines(splitLines(before), splitLines(after))
+
+	var b strings.Builder
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			i++
+			continue
+		}
+		// Изменения, между которыми меньше двух контекстов, попадают в один фрагмент
+		start := max(i-diffContext, 0)
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].op != ' ' {
+				end = j
+			} else if j-end > 2*diffContext {
+				break
+			}
+		}
+		end = min(end+diffContext+1, len(lines))
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", name, name)
+		}
+		writeHunk(&b, lines[start:end])
+		i = end
+	}
+	return b.String()
+}
+
+func writeHunk(b *strings.Builder, hunk []diffLine) {
+	oldLen, newLen := 0, 0
+	for _, l := range hunk {
+		if l.op != '+' {
+			oldLen++
+		}
+		if l.op != '-' {
+			newLen++
+		}
+	}
+	// Пустой диапазон указывает на строку перед ним
+	oldStart, newStart := hunk[0].before, hunk[0].after
+	if oldLen > 0 {
+		oldStart++
+	}
+	if newLen > 0 {
+		newStart++
+	}
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldLen, newStart, newLen)
+	for _, l := range hunk {
+		b.WriteByte(l.op)
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+}
+
+// diffLines строит построчную разницу по наибольшей общей подпоследовательности.
+// Общие начало и конец файлов (у исправленной заметки — всё тело) считаются неизменными
+// и в квадратичную таблицу не попадают, поэтому она строится только по области FrontMatter
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b)-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, diffLine{op: ' ', text: a[i], before: i, after: i})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			lines = append(lines, diffLine{op: ' ', text: midA[i], before: prefix + i, after: prefix + j})
+			i++
+			j++
+		case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{op: '-', text: midA[i], before: prefix + i, after: prefix + j})
+			i++
+		default:
+			lines = append(lines, diffLine{op: '+', text: midB[j], before: prefix + i, after: prefix + j})
+			j++
+		}
+	}
+
+	shift := len(b) - len(a)
+	for k := len(a) - suffix; k < len(a); k++ {
+		lines = append(lines, diffLine{op: ' ', text: a[k], before: k, after: k + shift})
+	}
+	return lines
+}
+
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
@@ -0,0 +1,259 @@
+package fixer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"gopkg.in/yaml.v3"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Разделители тегов, записанных одной строкой: tags: daily, go
+var tagSeparatorRe = regexp.MustCompile(`[,\s]+`)
+
+type Options struct {
+	// Порядок ключей FrontMatter; остальные ключи идут следом в исходном порядке
+	KeyOrder []string
+	// Часовой пояс дат без смещения; nil — UTC
+	Location *time.Location
+}
+
+// Change — исправленная заметка: исходное и новое содержимое
+type Change struct {
+	Path    string
+	RelPath string
+	Before  []byte
+	After   []byte
+}
+
+// FixDir исправляет FrontMatter всех заметок в root и возвращает только изменившиеся; файлы не записываются
+func FixDir(root string, opts Options) ([]Change, error) {
+	var changes []Change
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.ToLower(filepath.Ext(path)) != ".md" {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("не удалось прочитать файл %s: %v", path, err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		fixed, err := Fix(name, content, opts)
+		if err != nil {
+			log.Warnf("FrontMatter в %s не исправлен: %v", rel, err)
+			return nil
+		}
+		if !bytes.Equal(fixed, content) {
+			changes = append(changes, Change{Path: path, RelPath: filepath.ToSlash(rel), Before: content, After: fixed})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Fix нормализует FrontMatter заметки с именем name: добавляет дату из имени файла, приводит даты к ISO,
+// теги — к списку "#tag", упорядочивает ключи и выравнивает отступы. Комментарии и неизвестные ключи
+// сохраняются, потому что правится дерево yaml.Node, а не структура FrontMatter
+func Fix(name string, content []byte, opts Options) ([]byte, error) {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	raw, body, ok := splitFrontMatter(content)
+	var doc yaml.Node
+	if ok {
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return nil, fmt.Errorf("не удалось распарсить FrontMatter: %v", err)
+		}
+	} else {
+		body = append([]byte("\n"), content...)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("FrontMatter должен быть словарём ключей")
+	}
+
+	fixDate(mapping, name, opts.Location)
+	fixTags(mapping)
+	sortKeys(mapping, opts.KeyOrder)
+
+	// Заметку без FrontMatter трогаем, только если в нём появились поля
+	if len(mapping.Content) == 0 {
+		return content, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("не удалось записать FrontMatter: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("не удалось записать FrontMatter: %v", err)
+	}
+	buf.WriteString("---")
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
+
+// splitFrontMatter отделяет FrontMatter, только если первая строка файла — разделитель ---,
+// и ищет закрывающий разделитель отдельной строкой. Горизонтальные линии --- в тексте
+// заметки без FrontMatter не считаются его границами. body начинается сразу после закрывающего ---
+func splitFrontMatter(content []byte) (raw, body []byte, ok bool) {
+	first, rest, found := bytes.Cut(content, []byte("\n"))
+	if !found || string(bytes.TrimSuffix(first, []byte("\r"))) != "---" {
+		return nil, content, false
+	}
+	for offset := 0; offset < len(rest); {
+		line, _, _ := bytes.Cut(rest[offset:], []byte("\n"))
+		if string(bytes.TrimSuffix(line, []byte("\r"))) == "---" {
+			return rest[:offset], rest[offset+len("---"):], true
+		}
+		offset += len(line) + 1
+	}
+	return nil, content, false
+}
+
+// value возвращает узел значения ключа key в словаре
+func value(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func fixDate(mapping *yaml.Node, name string, loc *time.Location) {
+	node := value(mapping, "date")
+	if node == nil || (node.Kind == yaml.ScalarNode && (node.Tag == "!!null" || node.Value == "")) {
+		date, ok := vault.DateFromName(name, loc)
+		if !ok {
+			return
+		}
+		if node == nil {
+			node = &yaml.Node{Kind: yaml.ScalarNode}
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "date"}, node)
+		}
+		node.Tag, node.Value = "", date.Format(vault.DateLayout)
+		return
+	}
+	if node.Kind != yaml.ScalarNode {
+		return
+	}
+
+	date, err := vault.ParseDate(node.Value, loc)
+	if err != nil {
+		log.Warnf("Дата %s в заметке %s оставлена без изменений: %v", node.Value, name, err)
+		return
+	}
+	// Дата без времени остаётся датой, со временем — RFC3339 с часовым поясом
+	canonical := date.Format(time.RFC3339)
+	if !strings.Contains(node.Value, ":") {
+		canonical = date.Format(vault.DateLayout)
+	}
+	if canonical != node.Value {
+		node.Tag, node.Value = "", canonical
+	}
+}
+
+func fixTags(mapping *yaml.Node) {
+	node := value(mapping, "tags")
+	if node == nil {
+		return
+	}
+
+	var items []*yaml.Node
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return
+		}
+		for _, tag := range tagSeparatorRe.Split(node.Value, -1) {
+			items = append(items, &yaml.Node{Kind: yaml.ScalarNode, Value: tag})
+		}
+		// Строка тегов превращается в список; комментарии узла сохраняются
+		node.Kind, node.Style, node.Value = yaml.SequenceNode, 0, ""
+	case yaml.SequenceNode:
+		items = node.Content
+	default:
+		return
+	}
+
+	seen := make(map[string]bool)
+	var tags []*yaml.Node
+	for _, item := range items {
+		if item.Kind != yaml.ScalarNode {
+			tags = append(tags, item)
+			continue
+		}
+		tag := strings.TrimSpace(item.Value)
+		if tag == "" || tag == "#" {
+			continue
+		}
+		if !strings.HasPrefix(tag, "#") {
+			tag = "#" + tag
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		if tag != item.Value {
+			item.Tag, item.Value = "!!str", tag
+		}
+		tags = append(tags, item)
+	}
+	node.Tag, node.Content = "!!seq", tags
+}
+
+// sortKeys переставляет пары ключ-значение по order; ключи не из order сохраняют исходный порядок
+func sortKeys(mapping *yaml.Node, order []string) {
+	if len(order) == 0 {
+		return
+	}
+	rank := make(map[string]int, len(order))
+	for i, key := range order {
+		rank[key] = i
+	}
+	keyRank := func(key string) int {
+		if r, ok := rank[key]; ok {
+			return r
+		}
+		return len(order)
+	}
+
+	pairs := make([][2]*yaml.Node, 0, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{mapping.Content[i], mapping.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return keyRank(pairs[i][0].Value) < keyRank(pairs[j][0].Value)
+	})
+	mapping.Content = mapping.Content[:0]
+	for _, p := range pairs {
+		mapping.Content = append(mapping.Content, p[0], p[1])
+	}
+}
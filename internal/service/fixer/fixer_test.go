@@ -0,0 +1,194 @@
+package fixer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/schema"
+	"github.com/stretchr/testify/require"
+)
+
+var order = []string{"date", "author", "tags", "closed"}
+
+func TestFix(t *testing.T) {
+	testCases := []struct {
+		name     string
+		file     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "Уже нормализован",
+			file:     "2024-12-09",
+			content:  "---\ndate: 2024-12-09\nauthor: Иван\ntags:\n  - '#daily'\n---\n# День\n",
+			expected: "---\ndate: 2024-12-09\nauthor: Иван\ntags:\n  - '#daily'\n---\n# День\n",
+		},
+		{
+			name:    "Дата, теги, порядок ключей, отступы и комментарии",
+			file:    "2024-12-09",
+			content: "---\n# настроение за день\nmood: 7\nclosed: false\ntags: daily, go, daily\nauthor: \"Иван\"  # автор\nsleep:\n    hours: 6\n---\n# День\n",
+			expected: "---\ndate: 2024-12-09\nauthor: \"Иван\" # автор\ntags:\n  - '#daily'\n  - '#go'\nclosed: false\n" +
+				"# настроение за день\nmood: 7\nsleep:\n  hours: 6\n---\n# День\n",
+		},
+		{
+			name:     "Формат даты",
+			file:     "Идея",
+			content:  "---\ndate: 09.12.2024\ntags: ['#a', b]\n---\n",
+			expected: "---\ndate: 2024-12-09\ntags: ['#a', '#b']\n---\n",
+		},
+		{
+			name:     "Дата со временем",
+			file:     "Идея",
+			content:  "---\ndate: 2024-12-09 21:30\n---\n",
+			expected: "---\ndate: 2024-12-09T21:30:00Z\n---\n",
+		},
+		{
+			name:     "Нераспознанная дата не меняется",
+			file:     "2024-12-09",
+			content:  "---\ndate: вчера\n---\n",
+			expected: "---\ndate: вчера\n---\n",
+		},
+		{
+			name:     "Заметка без FrontMatter с датой в имени",
+			file:     "2024-12-09",
+			content:  "# День\n",
+			expected: "---\ndate: 2024-12-09\n---\n# День\n",
+		},
+		{
+			name:     "Заметка без FrontMatter и даты",
+			file:     "Идея",
+			content:  "# Идея\n",
+			expected: "# Идея\n",
+		},
+		{
+			name:     "Горизонтальные линии в тексте без FrontMatter",
+			file:     "2024-12-09",
+			content:  "Утро\n\n---\n\n---\nВечер\n",
+			expected: "---\ndate: 2024-12-09\n---\nУтро\n\n---\n\n---\nВечер\n",
+		},
+		{
+			name:     "Горизонтальная линия после FrontMatter",
+			file:     "2024-12-09",
+			content:  "---\ntags: daily\n---\nУтро\n\n---\nВечер\n",
+			expected: "---\ndate: 2024-12-09\ntags:\n  - '#daily'\n---\nУтро\n\n---\nВечер\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fixed, err := Fix(tc.file, []byte(tc.content), Options{KeyOrder: order})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(fixed))
+		})
+	}
+}
+
+func TestFix_Location(t *testing.T) {
+	fixed, err := Fix("a", []byte("---\ndate: 2024-12-09 21:30\n---\n"), Options{Location: time.FixedZone("MSK", 3*60*60)})
+
+	require.NoError(t, err)
+	require.Equal(t, "---\ndate: 2024-12-09T21:30:00+03:00\n---\n", string(fixed))
+}
+
+func TestFix_PassesValidation(t *testing.T) {
+	v, err := schema.New([]schema.Rule{{Field: "date", Required: true, Type: schema.TypeDate, MatchFilename: true}})
+	require.NoError(t, err)
+
+	for _, date := range []string{"2024-12-09", "09.12.2024", "2024-12-09 10:00", "2024-12-09T10:00:00+03:00"} {
+		t.Run(date, func(t *testing.T) {
+			fixed, err := Fix("2024-12-09", []byte("---\ndate: "+date+"\n---\n"), Options{Location: time.FixedZone("MSK", 3*60*60)})
+			require.NoError(t, err)
+
+			require.Empty(t, v.Validate("daily/2024-12-09.md", fixed))
+		})
+	}
+}
+
+func TestFix_Errors(t *testing.T) {
+	_, err := Fix("a", []byte("---\ndate: [\n---\n"), Options{})
+	require.Error(t, err)
+
+	_, err = Fix("a", []byte("---\n- a\n- b\n---\n"), Options{})
+	require.EqualError(t, err, "FrontMatter должен быть словарём ключей")
+}
+
+func TestFixDir(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "daily"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "daily", "2024-12-09.md"), []byte("# День"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "ok.md"), []byte("---\ntags:\n  - '#a'\n---\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "broken.md"), []byte("---\ntags: [\n---\n"), 0644))
+
+	changes, err := FixDir(root, Options{})
+
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "daily/2024-12-09.md", changes[0].RelPath)
+	require.Equal(t, "---\ndate: 2024-12-09\n---\n# День", string(changes[0].After))
+	// FixDir ничего не записывает
+	content, err := os.ReadFile(filepath.Join(root, "daily", "2024-12-09.md"))
+	require.NoError(t, err)
+	require.Equal(t, "# День", string(content))
+}
+
+func TestDiff(t *testing.T) {
+	before := "---\ntags: go\nauthor: Иван\n---\n1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	after := "---\ndate: 2024-12-09\nauthor: Иван\ntags:\n  - '#go'\n---\n1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+
+	require.Equal(t, `--- a/2024-12-09.md
++++ b/2024-12-09.md
+@@ -1,6 +1,8 @@
+ ---
+-tags: go
++date: 2024-12-09
+ author: Иван
++tags:
++  - '#go'
+ ---
+ 1
+ 2
+`, Diff("2024-12-09.md", []byte(before), []byte(after)))
+
+	require.Empty(t, Diff("a.md", []byte(before), []byte(before)))
+}
+
+func TestDiff_LongBody(t *testing.T) {
+	body := strings.Repeat("строка заметки\n", 20000)
+	before := "# День\n" + body
+	after := "---\ndate: 2024-12-09\n---\n# День\n" + body
+
+	require.Equal(t, `--- a/2024-12-09.md
++++ b/2024-12-09.md
+@@ -1,3 +1,6 @@
++---
++date: 2024-12-09
++---
+ # День
+ строка заметки
+ строка заметки
+`, Diff("2024-12-09.md", []byte(before), []byte(after)))
+}
+
+func TestDiff_SeparateHunks(t *testing.T) {
+	before := "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n"
+	after := "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n"
+
+	require.Equal(t, `--- a/x.md
++++ b/x.md
+@@ -1,4 +1,4 @@
+-a
++A
+ 1
+ 2
+ 3
+@@ -7,4 +7,4 @@
+ 6
+ 7
+ 8
+-b
++B
+`, Diff("x.md", []byte(before), []byte(after)))
+}
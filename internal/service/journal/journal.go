@@ -0,0 +1,250 @@
+package journal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultTemplate = `---
+date: {{.Date}}
+author: {{printf "%q" .Author}}
+tags:
+  - "#daily"
+closed: false
+---
+# {{.Date}}
+
+## Задачи
+{{range .Tasks}}{{.}}
+{{end}}`
+
+// Маркер открытой задачи в начале строки списка
+var taskMarkerRe = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+)\[[ /]\]`)
+
+// TemplateData — данные, доступные в шаблоне ежедневной заметки
+type TemplateData struct {
+	Date     string
+	Author   string
+	Previous string
+	// Строки незавершённых задач из предыдущей заметки
+	Tasks []string
+}
+
+type Journal struct {
+	srcDir string
+	author string
+	tmpl   *template.Template
+	// Настройки разбора дат заметок, те же, что у остальных команд
+	vaultOpts []vault.Option
+}
+
+// NewJournal создаёт журнал ежедневных заметок. Пустой templateText означает встроенный шаблон
+func NewJournal(srcDir, author, templateText string, opts ...vault.Option) (*Journal, error) {
+	if templateText == "" {
+		templateText = defaultTemplate
+	}
+	tmpl, err := template.New("daily").Parse(templateText)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось разобрать шаблон ежедневной заметки: %v", err)
+	}
+	return &Journal{srcDir: srcDir, author: author, tmpl: tmpl, vaultOpts: opts}, nil
+}
+
+// NewNote создаёт заметку за указанный день и переносит в неё незавершённые задачи
+// из предыдущей ежедневной заметки; там они помечаются как перенесённые ([>]).
+// Если closePrevious и в предыдущей заметке не осталось открытых задач, она помечается closed: true
+func (j *Journal) NewNote(date time.Time, closePrevious bool) (string, error) {
+	dateKey := date.Format(vault.DateLayout)
+	notePath := filepath.Join(j.srcDir, dateKey+".md")
+
+	if _, err := os.Stat(notePath); err == nil {
+		return "", fmt.Errorf("заметка на %s уже существует: %s", dateKey, notePath)
+	}
+
+	v, err := vault.Load(j.srcDir, j.vaultOpts...)
+	if err != nil {
+		return "", err
+	}
+
+	data := TemplateData{Date: dateKey, Author: j.author}
+
+	var carried []int
+	previous := previousNote(v, date)
+	if previous != nil {
+		data.Previous = previous.Name
+		data.Tasks, carried, err = openTaskLines(previous)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := j.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("не удалось заполнить шаблон ежедневной заметки: %v", err)
+	}
+	if err := os.WriteFile(notePath, buf.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("не удалось записать заметку: %v", err)
+	}
+
+	log.WithFields(log.Fields{
+		"file":  notePath,
+		"tasks": len(data.Tasks),
+	}).Info("Ежедневная заметка создана")
+
+	if len(carried) > 0 {
+		if err := migrateTasks(previous.Path, carried); err != nil {
+			return "", err
+		}
+	}
+
+	if previous != nil && closePrevious {
+		// Закрываем, только если после переноса в заметке не осталось открытых задач
+		open, err := j.hasOpenTasks(previous.Path)
+		if err != nil {
+			return "", err
+		}
+		if open {
+			log.WithFields(log.Fields{
+				"file": previous.Path,
+			}).Info("В предыдущей заметке остались открытые задачи, она не закрывается")
+		} else {
+			if err := closeNote(previous.Path); err != nil {
+				return "", err
+			}
+			log.WithFields(log.Fields{
+				"file": previous.Path,
+			}).Info("Предыдущая заметка закрыта")
+		}
+	}
+
+	return notePath, nil
+}
+
+// previousNote ищет последнюю заметку с датой раньше указанной
+func previousNote(v *vault.Vault, date time.Time) *vault.Note {
+	day := date.Format(vault.DateLayout)
+
+	var previous *vault.Note
+	var previousDay string
+	for _, note := range v.Notes {
+		noteDate, ok := note.Date()
+		if !ok {
+			continue
+		}
+		key := noteDate.Format(vault.DateLayout)
+		if key < day && key > previousDay {
+			previous, previousDay = note, key
+		}
+	}
+	return previous
+}
+
+// openTaskLines возвращает исходные строки открытых задач, сохраняя вложенность,
+// и их номера в файле заметки
+func openTaskLines(note *vault.Note) ([]string, []int, error) {
+	content, err := os.ReadFile(note.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("не удалось прочитать предыдущую заметку: %v", err)
+	}
+	lines := strings.Split(string(content), "\n")
+
+	var result []string
+	var numbers []int
+	minIndent := -1
+	for _, task := range note.Tasks {
+		if !task.IsOpen() || task.Line < 1 || task.Line > len(lines) {
+			continue
+		}
+		line := strings.TrimRight(lines[task.Line-1], "\r")
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if minIndent == -1 || indent < minIndent {
+			minIndent = indent
+		}
+		result = append(result, line)
+		numbers = append(numbers, task.Line)
+	}
+
+	// Если родительская задача закрыта, дочерние поднимаются на верхний уровень
+	for i, line := range result {
+		result[i] = line[minIndent:]
+	}
+	return result, numbers, nil
+}
+
+// migrateTasks помечает задачи на указанных строках как перенесённые: - [ ] → - [>]
+func migrateTasks(path string, numbers []int) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать заметку %s: %v", path, err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for _, n := range numbers {
+		if n >= 1 && n <= len(lines) {
+			lines[n-1] = taskMarkerRe.ReplaceAllString(lines[n-1], "${1}[>]")
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return fmt.Errorf("не удалось записать заметку %s: %v", path, err)
+	}
+	return nil
+}
+
+// hasOpenTasks перечитывает заметку и сообщает, остались ли в ней открытые задачи
+func (j *Journal) hasOpenTasks(path string) (bool, error) {
+	note, err := vault.ParseNote(path, j.srcDir, j.vaultOpts...)
+	if err != nil {
+		return false, err
+	}
+	for _, task := range note.Tasks {
+		if task.IsOpen() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// closeNote выставляет closed: true во FrontMatter, не трогая остальное содержимое файла
+func closeNote(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать заметку %s: %v", path, err)
+	}
+
+	updated := setClosed(string(content))
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("не удалось записать заметку %s: %v", path, err)
+	}
+	return nil
+}
+
+func setClosed(content string) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "---\nclosed: true\n---\n" + content
+	}
+
+	for i := 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "---" {
+			// Поля closed нет — добавляем его перед закрывающим разделителем
+			lines = append(lines[:i], append([]string{"closed: true"}, lines[i:]...)...)
+			break
+		}
+		if strings.HasPrefix(lines[i], "closed:") {
+			lines[i] = "closed: true"
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
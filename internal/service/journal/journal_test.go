@@ -0,0 +1,214 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+var today = time.Date(2024, 12, 10, 8, 0, 0, 0, time.UTC)
+
+func TestJournal_NewNote_CarriesOverOpenTasks(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	previous := `---
+date: 2024-12-09
+closed: false
+---
+# 2024-12-09
+- [x] выполнена
+  - [ ] вложенная открытая 📅 2024-12-12
+- [ ] открытая
+- [-] отменённая
+`
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "2024-12-09.md"), []byte(previous), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "2024-12-01.md"), []byte("---\ndate: 2024-12-01\n---\n- [ ] старая\n"), 0644))
+
+	sut, err := NewJournal(srcDir, "ANkulagin", "")
+	require.NoError(t, err)
+
+	notePath, err := sut.NewNote(today, true)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(srcDir, "2024-12-10.md"), notePath)
+
+	content, err := os.ReadFile(notePath)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "date: 2024-12-10\nauthor: \"ANkulagin\"\n")
+	require.Contains(t, string(content), "- [ ] вложенная открытая 📅 2024-12-12\n- [ ] открытая\n")
+	require.NotContains(t, string(content), "старая")
+	require.NotContains(t, string(content), "отменённая")
+
+	// Перенесённые задачи больше не считаются открытыми в предыдущей заметке
+	content, err = os.ReadFile(filepath.Join(srcDir, "2024-12-09.md"))
+	require.NoError(t, err)
+	require.Equal(t, `---
+date: 2024-12-09
+closed: true
+---
+# 2024-12-09
+- [x] выполнена
+  - [>] вложенная открытая 📅 2024-12-12
+- [>] открытая
+- [-] отменённая
+`, string(content))
+
+	v, err := vault.Load(srcDir)
+	require.NoError(t, err)
+	open := 0
+	for _, note := range v.Notes {
+		for _, task := range note.Tasks {
+			if task.IsOpen() {
+				open++
+			}
+		}
+	}
+	require.Equal(t, 3, open, "задачи из 2024-12-09 учитываются только в новой заметке")
+}
+
+func TestJournal_NewNote_VaultOptions(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	// Дата предыдущей заметки есть только в имени файла
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "2024-12-09.md"), []byte("- [ ] из имени файла\n"), 0644))
+
+	sut, err := NewJournal(srcDir, "", "{{range .Tasks}}{{.}}{{end}}", vault.WithDateFallback(vault.DateFromFilename))
+	require.NoError(t, err)
+
+	notePath, err := sut.NewNote(today, false)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(notePath)
+	require.NoError(t, err)
+	require.Equal(t, "- [ ] из имени файла", string(content))
+}
+
+func TestJournal_NewNote_QuotesAuthor(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	sut, err := NewJournal(srcDir, `- Иван: "#1"`, "")
+	require.NoError(t, err)
+
+	notePath, err := sut.NewNote(today, false)
+	require.NoError(t, err)
+
+	note, err := vault.ParseNote(notePath, srcDir)
+	require.NoError(t, err)
+	require.Equal(t, `- Иван: "#1"`, note.FrontMatter.Author)
+}
+
+func TestJournal_NewNote_ClosesResolvedPrevious(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	previousPath := filepath.Join(srcDir, "2024-12-09.md")
+	require.NoError(t, os.WriteFile(previousPath, []byte("---\ndate: 2024-12-09\nclosed: false\n---\n- [x] готово\n"), 0644))
+
+	sut, err := NewJournal(srcDir, "ANkulagin", "{{.Date}} после {{.Previous}}")
+	require.NoError(t, err)
+
+	notePath, err := sut.NewNote(today, true)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(notePath)
+	require.NoError(t, err)
+	require.Equal(t, "2024-12-10 после 2024-12-09", string(content))
+
+	content, err = os.ReadFile(previousPath)
+	require.NoError(t, err)
+	require.Equal(t, "---\ndate: 2024-12-09\nclosed: true\n---\n- [x] готово\n", string(content))
+}
+
+func TestJournal_HasOpenTasks(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	sut, err := NewJournal(srcDir, "", "")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{name: "открытая задача", content: "- [x] готово\n- [ ] осталась\n", expected: true},
+		{name: "задача в работе", content: "- [/] начата\n", expected: true},
+		{name: "перенесённые и закрытые", content: "- [>] перенесена\n- [x] готово\n- [-] отменена\n", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(srcDir, "note.md")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+
+			open, err := sut.hasOpenTasks(path)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, open)
+		})
+	}
+}
+
+func TestJournal_NewNote_AlreadyExists(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "src_dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "2024-12-10.md"), []byte("# Сегодня"), 0644))
+
+	sut, err := NewJournal(srcDir, "", "")
+	require.NoError(t, err)
+
+	_, err = sut.NewNote(today, false)
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "уже существует")
+}
+
+func TestNewJournal_InvalidTemplate(t *testing.T) {
+	_, err := NewJournal("", "", "{{.Date")
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "не удалось разобрать шаблон ежедневной заметки")
+}
+
+func TestSetClosed(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "Замена существующего значения",
+			content:  "---\ndate: 2024-12-09\nclosed: false\n---\nтекст",
+			expected: "---\ndate: 2024-12-09\nclosed: true\n---\nтекст",
+		},
+		{
+			name:     "Добавление поля",
+			content:  "---\ndate: 2024-12-09\n---\nтекст",
+			expected: "---\ndate: 2024-12-09\nclosed: true\n---\nтекст",
+		},
+		{
+			name:     "Заметка без FrontMatter",
+			content:  "текст",
+			expected: "---\nclosed: true\n---\nтекст",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, setClosed(tc.content))
+		})
+	}
+}
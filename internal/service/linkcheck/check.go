@@ -0,0 +1,189 @@
+package linkcheck
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+// Виды проблем в отчёте
+const (
+	KindBrokenWikiLink    = "broken_wikilink"
+	KindBrokenLink        = "broken_link"
+	KindMissingAttachment = "missing_attachment"
+	KindMissingHeading    = "missing_heading"
+	KindMissingBlock      = "missing_block"
+	KindOrphan            = "orphan"
+)
+
+type Problem struct {
+	Kind string `json:"kind"`
+	// Путь заметки относительно корня хранилища
+	File string `json:"file"`
+	// Номер строки ссылки; 0 — проблема относится ко всей заметке
+	Line    int    `json:"line,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Message string `json:"message"`
+}
+
+// IsWarning сообщает, что проблема не считается ошибкой: заметка без входящих ссылок
+// (например, обычная ежедневная) ничего не ломает
+func (p Problem) IsWarning() bool {
+	return p.Kind == KindOrphan
+}
+
+type Options struct {
+	// Сообщать о заметках без входящих ссылок
+	Orphans bool
+}
+
+// Check проверяет ссылки всех заметок хранилища, ничего не записывая на диск
+func Check(v *vault.Vault, opts Options) ([]Problem, error) {
+	files, err := attachments(v.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []Problem{}
+	for _, note := range v.Notes {
+		for _, link := range note.Links {
+			if link.IsExternal() {
+				continue
+			}
+			if p, ok := checkLink(v, files, note, link); ok {
+				problems = append(problems, p)
+			}
+		}
+	}
+
+	if opts.Orphans {
+		backlinks := v.Backlinks()
+		for _, note := range v.Notes {
+			if backlinks[note] == 0 {
+				problems = append(problems, Problem{
+					Kind:    KindOrphan,
+					File:    filepath.ToSlash(note.RelPath),
+					Message: "на заметку нет ссылок из других заметок",
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		return problems[i].Line < problems[j].Line
+	})
+	return problems, nil
+}
+
+func checkLink(v *vault.Vault, files fileIndex, note *vault.Note, link vault.Link) (Problem, bool) {
+	p := Problem{File: filepath.ToSlash(note.RelPath), Line: link.Line, Target: linkText(link)}
+
+	target := v.Resolve(note, link)
+	if target == nil {
+		ext := strings.ToLower(path.Ext(link.Target))
+		switch {
+		case link.Wiki && ext != "" && ext != ".md":
+			if files.hasName(link.Target) {
+				return p, false
+			}
+			p.Kind, p.Message = KindMissingAttachment, fmt.Sprintf("вложение %s не найдено", link.Target)
+		case link.Wiki:
+			p.Kind, p.Message = KindBrokenWikiLink, fmt.Sprintf("заметка %s не найдена", link.Target)
+		case ext != ".md":
+			if files.hasPath(linkPath(note, link.Target)) {
+				return p, false
+			}
+			p.Kind, p.Message = KindMissingAttachment, fmt.Sprintf("файл %s не найден", link.Target)
+		default:
+			p.Kind, p.Message = KindBrokenLink, fmt.Sprintf("заметка %s не найдена", link.Target)
+		}
+		return p, true
+	}
+
+	switch {
+	case link.Anchor == "":
+		return p, false
+	case strings.HasPrefix(link.Anchor, "^"):
+		if target.HasBlock(link.Anchor[1:]) {
+			return p, false
+		}
+		p.Kind, p.Message = KindMissingBlock, fmt.Sprintf("блок %s не найден в заметке %s", link.Anchor, target.RelPath)
+	default:
+		if target.HasHeading(link.Anchor) {
+			return p, false
+		}
+		p.Kind, p.Message = KindMissingHeading, fmt.Sprintf("заголовок %s не найден в заметке %s", link.Anchor, target.RelPath)
+	}
+	return p, true
+}
+
+// linkText восстанавливает цель ссылки в том виде, в каком она записана в заметке
+func linkText(link vault.Link) string {
+	target := link.Target
+	if link.Anchor != "" {
+		target += "#" + link.Anchor
+	}
+	if link.Wiki {
+		return "[[" + target + "]]"
+	}
+	return target
+}
+
+// linkPath возвращает путь файла Markdown-ссылки относительно корня хранилища
+func linkPath(note *vault.Note, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return path.Clean(strings.TrimPrefix(target, "/"))
+	}
+	return path.Join(path.Dir(filepath.ToSlash(note.RelPath)), target)
+}
+
+// fileIndex — файлы хранилища, кроме заметок: по пути относительно корня и по имени,
+// потому что Obsidian находит вложение ![[image.png]] в любой папке
+type fileIndex struct {
+	paths map[string]bool
+	names map[string]bool
+}
+
+func (f fileIndex) hasPath(rel string) bool {
+	return f.paths[rel]
+}
+
+func (f fileIndex) hasName(target string) bool {
+	target = filepath.ToSlash(target)
+	return f.paths[target] || f.names[strings.ToLower(path.Base(target))]
+}
+
+// attachments собирает файлы хранилища, пропуская служебные папки вроде .obsidian и .git
+func attachments(root string) (fileIndex, error) {
+	files := fileIndex{paths: make(map[string]bool), names: make(map[string]bool)}
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files.paths[filepath.ToSlash(rel)] = true
+		files.names[strings.ToLower(info.Name())] = true
+		return nil
+	})
+	if err != nil {
+		return files, fmt.Errorf("не удалось обойти хранилище %s: %v", root, err)
+	}
+	return files, nil
+}
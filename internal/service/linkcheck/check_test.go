@@ -0,0 +1,94 @@
+package linkcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func writeVault(t *testing.T, files map[string]string) *vault.Vault {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	v, err := vault.Load(root)
+	require.NoError(t, err)
+	return v
+}
+
+func TestCheck(t *testing.T) {
+	v := writeVault(t, map[string]string{
+		"index.md": "# Главная\n" +
+			"[[Идея]] [[Нет такой]]\n" +
+			"[[Идея#Итоги]] [[Идея#Планы]]\n" +
+			"[[Идея#^abc]] [[Идея#^нет]]\n" +
+			"![[photo.png]] ![[lost.png]]\n" +
+			"[проект](projects/alpha.md) [нет](projects/beta.md#Раздел)\n" +
+			"[файл](files/doc.pdf) [нет](files/none.pdf) [сайт](https://example.com)\n" +
+			"[к себе](#Главная) [[#Нет раздела]]",
+		"Идея.md":            "## Итоги\nмысль ^abc\n[[index]]",
+		"projects/alpha.md":  "[назад](../index.md)",
+		"files/doc.pdf":      "pdf",
+		"images/photo.png":   "png",
+		".obsidian/lost.png": "png",
+		"orphans/Забытая.md": "текст",
+	})
+
+	problems, err := Check(v, Options{Orphans: true})
+
+	require.NoError(t, err)
+	require.Equal(t, []Problem{
+		{Kind: KindBrokenWikiLink, File: "index.md", Line: 2, Target: "[[Нет такой]]", Message: "заметка Нет такой не найдена"},
+		{Kind: KindMissingHeading, File: "index.md", Line: 3, Target: "[[Идея#Планы]]", Message: "заголовок Планы не найден в заметке Идея.md"},
+		{Kind: KindMissingBlock, File: "index.md", Line: 4, Target: "[[Идея#^нет]]", Message: "блок ^нет не найден в заметке Идея.md"},
+		{Kind: KindMissingAttachment, File: "index.md", Line: 5, Target: "[[lost.png]]", Message: "вложение lost.png не найдено"},
+		{Kind: KindBrokenLink, File: "index.md", Line: 6, Target: "projects/beta.md#Раздел", Message: "заметка projects/beta.md не найдена"},
+		{Kind: KindMissingAttachment, File: "index.md", Line: 7, Target: "files/none.pdf", Message: "файл files/none.pdf не найден"},
+		{Kind: KindMissingHeading, File: "index.md", Line: 8, Target: "[[#Нет раздела]]", Message: "заголовок Нет раздела не найден в заметке index.md"},
+		{Kind: KindOrphan, File: "orphans/Забытая.md", Message: "на заметку нет ссылок из других заметок"},
+	}, problems)
+	require.True(t, problems[len(problems)-1].IsWarning())
+	require.False(t, problems[0].IsWarning())
+}
+
+func TestCheck_NoOrphans(t *testing.T) {
+	v := writeVault(t, map[string]string{"a.md": "текст"})
+
+	problems, err := Check(v, Options{})
+
+	require.NoError(t, err)
+	require.Empty(t, problems)
+}
+
+func TestWrite(t *testing.T) {
+	problems := []Problem{
+		{Kind: KindBrokenWikiLink, File: "a.md", Line: 3, Target: "[[b]]", Message: "заметка b не найдена"},
+		{Kind: KindOrphan, File: "c.md", Message: "на заметку нет ссылок из других заметок"},
+	}
+
+	var text bytes.Buffer
+	require.NoError(t, Write(&text, problems, FormatText))
+	require.Equal(t, "a.md:3: broken_wikilink: заметка b не найдена\n"+
+		"c.md: orphan: на заметку нет ссылок из других заметок\n"+
+		"Найдено проблем: 2\n", text.String())
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, problems, FormatJSON))
+	var decoded []Problem
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(t, problems, decoded)
+	require.NotContains(t, out.String(), `"line": 0`)
+
+	var empty bytes.Buffer
+	require.NoError(t, Write(&empty, nil, FormatJSON))
+	require.Equal(t, "[]\n", empty.String())
+
+	require.Error(t, Write(&empty, nil, "xml"))
+}
@@ -0,0 +1,52 @@
+package linkcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+func Write(w io.Writer, problems []Problem, format string) error {
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return WriteText(w, problems)
+	case FormatJSON:
+		return WriteJSON(w, problems)
+	default:
+		return fmt.Errorf("неизвестный формат отчёта: %s", format)
+	}
+}
+
+func WriteJSON(w io.Writer, problems []Problem) error {
+	if problems == nil {
+		problems = []Problem{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(problems)
+}
+
+// WriteText пишет проблемы в формате file:line: сообщение, привычном для редакторов и pre-commit
+func WriteText(w io.Writer, problems []Problem) error {
+	var b strings.Builder
+	for _, p := range problems {
+		pos := p.File
+		if p.Line > 0 {
+			pos = fmt.Sprintf("%s:%d", p.File, p.Line)
+		}
+		fmt.Fprintf(&b, "%s: %s: %s\n", pos, p.Kind, p.Message)
+	}
+	if len(problems) == 0 {
+		b.WriteString("Проблем не найдено\n")
+	} else {
+		fmt.Fprintf(&b, "Найдено проблем: %d\n", len(problems))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,94 @@
+package mathml
+
+import (
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokSpace
+	tokCommand
+	tokNumber
+	tokChar
+	tokOpen
+	tokClose
+	tokSup
+	tokSub
+	tokAmp
+	tokNewline
+)
+
+type token struct {
+	kind tokenKind
+	// Имя команды без \, число или символ
+	text string
+	// Исходный текст токена; нужен для \text{...}
+	raw string
+}
+
+func tokenize(src string) []token {
+	runes := []rune(src)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			j := i
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokSpace, text: " ", raw: string(runes[i:j])})
+			i = j
+		case r == '\\':
+			switch {
+			case i+1 >= len(runes):
+				tokens = append(tokens, token{kind: tokChar, text: "\\", raw: "\\"})
+				i++
+			case runes[i+1] == '\\':
+				tokens = append(tokens, token{kind: tokNewline, raw: `\\`})
+				i += 2
+			case isLetter(runes[i+1]):
+				j := i + 1
+				for j < len(runes) && isLetter(runes[j]) {
+					j++
+				}
+				tokens = append(tokens, token{kind: tokCommand, text: string(runes[i+1 : j]), raw: string(runes[i:j])})
+				i = j
+			default:
+				tokens = append(tokens, token{kind: tokCommand, text: string(runes[i+1]), raw: string(runes[i : i+2])})
+				i += 2
+			}
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' && j+1 < len(runes) && unicode.IsDigit(runes[j+1])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: string(runes[i:j]), raw: string(runes[i:j])})
+			i = j
+		default:
+			kind := tokChar
+			switch r {
+			case '{':
+				kind = tokOpen
+			case '}':
+				kind = tokClose
+			case '^':
+				kind = tokSup
+			case '_':
+				kind = tokSub
+			case '&':
+				kind = tokAmp
+			}
+			tokens = append(tokens, token{kind: kind, text: string(r), raw: string(r)})
+			i++
+		}
+	}
+	return append(tokens, token{kind: tokEOF})
+}
+
+// isLetter допускает только латиницу: имена команд TeX состоят из ASCII-букв
+func isLetter(r rune) bool {
+	return r < unicode.MaxASCII && unicode.IsLetter(r)
+}
@@ -0,0 +1,22 @@
+package mathml
+
+import (
+	"fmt"
+	"html"
+)
+
+// Render переводит формулу LaTeX в MathML. display — выносная формула ($$...$$)
+func Render(tex string, display bool) (string, error) {
+	p := &parser{tokens: tokenize(tex), display: display}
+	items, err := p.parseList(func(token) bool { return false })
+	if err != nil {
+		return "", fmt.Errorf("ошибка в формуле %q: %v", tex, err)
+	}
+
+	attrs := ""
+	if display {
+		attrs = ` display="block"`
+	}
+	return fmt.Sprintf(`<math xmlns="http://www.w3.org/1998/Math/MathML"%s><semantics>%s<annotation encoding="application/x-tex">%s</annotation></semantics></math>`,
+		attrs, mrow(items), html.EscapeString(tex)), nil
+}
@@ -0,0 +1,118 @@
+package mathml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		tex     string
+		display bool
+		want    string
+	}{
+		{
+			name: "Индексы и степени",
+			tex:  "x_i^2 + a_{n-1}",
+			want: "<mrow><msubsup><mi>x</mi><mi>i</mi><mn>2</mn></msubsup><mo>+</mo><msub><mi>a</mi><mrow><mi>n</mi><mo>−</mo><mn>1</mn></mrow></msub></mrow>",
+		},
+		{
+			name: "Степень из одной цифры",
+			tex:  "x^23",
+			want: "<mrow><msup><mi>x</mi><mn>2</mn></msup><mn>3</mn></mrow>",
+		},
+		{
+			name: "Дробь и корень",
+			tex:  `\frac{1}{\sqrt[3]{x}}`,
+			want: "<mfrac><mn>1</mn><mroot><mi>x</mi><mn>3</mn></mroot></mfrac>",
+		},
+		{
+			name: "Греческие буквы и операторы",
+			tex:  `\alpha \leq \pi \cdot r`,
+			want: "<mrow><mi>α</mi><mo>≤</mo><mi>π</mi><mo>⋅</mo><mi>r</mi></mrow>",
+		},
+		{
+			name: "Сумма в строке",
+			tex:  `\sum_{i=1}^n i`,
+			want: "<mrow><msubsup><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></msubsup><mi>i</mi></mrow>",
+		},
+		{
+			name:    "Сумма в выносной формуле",
+			tex:     `\lim_{x \to 0} f`,
+			display: true,
+			want:    `<mrow><munder><mo movablelimits="true">lim</mo><mrow><mi>x</mi><mo>→</mo><mn>0</mn></mrow></munder><mi>f</mi></mrow>`,
+		},
+		{
+			name: "Скобки \\left \\right",
+			tex:  `\left( \frac{a}{b} \right)`,
+			want: `<mrow><mo fence="true" stretchy="true">(</mo><mfrac><mi>a</mi><mi>b</mi></mfrac><mo fence="true" stretchy="true">)</mo></mrow>`,
+		},
+		{
+			name: "Текст и шрифты",
+			tex:  `\text{если } \mathbf{v} \mathbb{R}`,
+			want: `<mrow><mtext>если </mtext><mi mathvariant="bold">v</mi><mi mathvariant="double-struck">R</mi></mrow>`,
+		},
+		{
+			name: "Акценты и функции",
+			tex:  `\hat{x} \sin\theta`,
+			want: `<mrow><mover accent="true"><mi>x</mi><mo>^</mo></mover><mi>sin</mi><mi>θ</mi></mrow>`,
+		},
+		{
+			name: "Матрица",
+			tex:  `\begin{pmatrix} a & b \\ c & d \\ \end{pmatrix}`,
+			want: `<mrow><mo fence="true" stretchy="true">(</mo><mtable><mtr><mtd><mi>a</mi></mtd><mtd><mi>b</mi></mtd></mtr><mtr><mtd><mi>c</mi></mtd><mtd><mi>d</mi></mtd></mtr></mtable><mo fence="true" stretchy="true">)</mo></mrow>`,
+		},
+		{
+			name: "Система уравнений",
+			tex:  `f = \begin{cases} 1 & x > 0 \\ 0 & x \le 0 \end{cases}`,
+			want: `<mrow><mi>f</mi><mo>=</mo><mrow><mo fence="true" stretchy="true">{</mo><mtable columnalign="left"><mtr><mtd><mn>1</mn></mtd><mtd><mrow><mi>x</mi><mo>&gt;</mo><mn>0</mn></mrow></mtd></mtr><mtr><mtd><mn>0</mn></mtd><mtd><mrow><mi>x</mi><mo>≤</mo><mn>0</mn></mrow></mtd></mtr></mtable></mrow></mrow>`,
+		},
+		{
+			name: "Штрих и пробелы",
+			tex:  `f'(x)\,dx`,
+			want: `<mrow><msup><mi>f</mi><mo>′</mo></msup><mo>(</mo><mi>x</mi><mo>)</mo><mspace width="0.167em"></mspace><mi>d</mi><mi>x</mi></mrow>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.tex, tt.display)
+			require.NoError(t, err)
+			require.Contains(t, got, "<semantics>"+tt.want+"<annotation")
+		})
+	}
+}
+
+func TestRender_Wrapper(t *testing.T) {
+	got, err := Render("a<b", true)
+
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(got, `<math xmlns="http://www.w3.org/1998/Math/MathML" display="block">`))
+	require.Contains(t, got, `<annotation encoding="application/x-tex">a&lt;b</annotation>`)
+}
+
+func TestRender_Errors(t *testing.T) {
+	tests := []struct {
+		tex  string
+		want string
+	}{
+		{tex: `\foo`, want: `неизвестная команда \foo`},
+		{tex: `\frac{1}`, want: "не хватает аргумента"},
+		{tex: `{x`, want: "ожидалась }"},
+		{tex: `x}`, want: "лишняя }"},
+		{tex: `\left( x`, want: `\left без \right`},
+		{tex: `\begin{foo}x\end{foo}`, want: "неизвестное окружение foo"},
+		{tex: `\begin{matrix}x\end{pmatrix}`, want: `ожидался \end{matrix}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tex, func(t *testing.T) {
+			_, err := Render(tt.tex, false)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
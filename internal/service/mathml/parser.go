@@ -0,0 +1,483 @@
+package mathml
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+type parser struct {
+	tokens  []token
+	pos     int
+	display bool
+	// mathvariant из \mathbf и подобных команд
+	variant string
+}
+
+func (p *parser) skipSpaces() {
+	for p.tokens[p.pos].kind == tokSpace {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() token {
+	p.skipSpaces()
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) error {
+	if t := p.next(); t.kind != kind {
+		return fmt.Errorf("ожидалась %s", what)
+	}
+	return nil
+}
+
+// parseList разбирает последовательность элементов до токена, на котором stop вернёт true
+func (p *parser) parseList(stop func(token) bool) ([]string, error) {
+	var items []string
+	for {
+		t := p.peek()
+		if t.kind == tokEOF || stop(t) {
+			return items, nil
+		}
+		item, err := p.parseScripted()
+		if err != nil {
+			return nil, err
+		}
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+}
+
+func mrow(items []string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+	return "<mrow>" + strings.Join(items, "") + "</mrow>"
+}
+
+// parseScripted разбирает элемент с индексами: x_i, x^2, \sum_{i=1}^n
+func (p *parser) parseScripted() (string, error) {
+	base := "<mrow></mrow>"
+	limits := false
+	if t := p.peek(); t.kind != tokSup && t.kind != tokSub {
+		var err error
+		if base, limits, err = p.parseAtom(); err != nil {
+			return "", err
+		}
+		if base == "" {
+			return "", nil
+		}
+	}
+
+	var sub, sup string
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokSub && sub == "":
+			p.next()
+			arg, err := p.parseArgument()
+			if err != nil {
+				return "", err
+			}
+			sub = arg
+			continue
+		case t.kind == tokSup && sup == "":
+			p.next()
+			arg, err := p.parseArgument()
+			if err != nil {
+				return "", err
+			}
+			sup = arg
+			continue
+		case t.kind == tokChar && t.text == "'" && sup == "":
+			p.next()
+			sup = "<mo>′</mo>"
+			continue
+		}
+		break
+	}
+
+	under, over, both := "msub", "msup", "msubsup"
+	if limits && p.display {
+		under, over, both = "munder", "mover", "munderover"
+	}
+	switch {
+	case sub != "" && sup != "":
+		return fmt.Sprintf("<%s>%s%s%s</%s>", both, base, sub, sup, both), nil
+	case sub != "":
+		return fmt.Sprintf("<%s>%s%s</%s>", under, base, sub, under), nil
+	case sup != "":
+		return fmt.Sprintf("<%s>%s%s</%s>", over, base, sup, over), nil
+	}
+	return base, nil
+}
+
+// parseArgument разбирает аргумент команды или индекса: {группа} или один элемент.
+// Как в TeX, x^23 — это x², за которым следует 3
+func (p *parser) parseArgument() (string, error) {
+	t := p.peek()
+	if t.kind == tokNumber && len(t.text) > 1 {
+		p.tokens[p.pos].text = t.text[1:]
+		return p.number(t.text[:1]), nil
+	}
+	if t.kind == tokEOF || t.kind == tokClose {
+		return "", fmt.Errorf("не хватает аргумента")
+	}
+	item, _, err := p.parseAtom()
+	if err != nil {
+		return "", err
+	}
+	if item == "" {
+		item = "<mrow></mrow>"
+	}
+	return item, nil
+}
+
+func (p *parser) parseGroup() (string, error) {
+	if err := p.expect(tokOpen, "{"); err != nil {
+		return "", err
+	}
+	items, err := p.parseList(func(t token) bool { return t.kind == tokClose })
+	if err != nil {
+		return "", err
+	}
+	if err := p.expect(tokClose, "}"); err != nil {
+		return "", err
+	}
+	return mrow(items), nil
+}
+
+// parseAtom разбирает один элемент без индексов; limits сообщает, что это оператор с пределами
+func (p *parser) parseAtom() (item string, limits bool, err error) {
+	t := p.peek()
+	switch t.kind {
+	case tokOpen:
+		item, err = p.parseGroup()
+		return item, false, err
+	case tokClose:
+		return "", false, fmt.Errorf("лишняя }")
+	case tokAmp:
+		return "", false, fmt.Errorf("& вне окружения")
+	case tokNewline:
+		p.next()
+		return `<mspace linebreak="newline"></mspace>`, false, nil
+	case tokNumber:
+		p.next()
+		return p.number(t.text), false, nil
+	case tokCommand:
+		p.next()
+		return p.parseCommand(t.text)
+	}
+
+	p.next()
+	r := []rune(t.text)[0]
+	if unicode.IsLetter(r) {
+		return p.identifier(t.text), false, nil
+	}
+	switch t.text {
+	case "-":
+		return "<mo>−</mo>", false, nil
+	case "*":
+		return "<mo>∗</mo>", false, nil
+	case "'":
+		return "<mo>′</mo>", false, nil
+	}
+	return op(t.text), false, nil
+}
+
+func (p *parser) identifier(name string) string {
+	if p.variant != "" {
+		return fmt.Sprintf(`<mi mathvariant="%s">%s</mi>`, p.variant, html.EscapeString(name))
+	}
+	return "<mi>" + html.EscapeString(name) + "</mi>"
+}
+
+func (p *parser) number(n string) string {
+	if p.variant != "" && p.variant != "normal" && p.variant != "italic" {
+		return fmt.Sprintf(`<mn mathvariant="%s">%s</mn>`, p.variant, n)
+	}
+	return "<mn>" + n + "</mn>"
+}
+
+func op(s string) string {
+	return "<mo>" + html.EscapeString(s) + "</mo>"
+}
+
+func (p *parser) parseCommand(name string) (string, bool, error) {
+	if s, ok := identifiers[name]; ok {
+		return p.identifier(s), false, nil
+	}
+	if s, ok := operators[name]; ok {
+		return op(s), false, nil
+	}
+	if s, ok := largeOperators[name]; ok {
+		return op(s), true, nil
+	}
+	if functions[name] {
+		return "<mi>" + name + "</mi>", false, nil
+	}
+	if limitFunctions[name] {
+		return "<mo movablelimits=\"true\">" + name + "</mo>", true, nil
+	}
+	if width, ok := spaces[name]; ok {
+		return fmt.Sprintf(`<mspace width="%s"></mspace>`, width), false, nil
+	}
+	if ignored[name] {
+		return "", false, nil
+	}
+	if s, ok := accents[name]; ok {
+		arg, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf(`<mover accent="true">%s%s</mover>`, arg, op(s)), false, nil
+	}
+	if s, ok := underAccents[name]; ok {
+		arg, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf(`<munder accentunder="true">%s%s</munder>`, arg, op(s)), false, nil
+	}
+	if variant, ok := fontVariants[name]; ok {
+		saved := p.variant
+		p.variant = variant
+		arg, err := p.parseArgument()
+		p.variant = saved
+		return arg, false, err
+	}
+	if size, ok := bigSizes[name]; ok {
+		d, err := p.delimiter()
+		if err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf(`<mo minsize="%s" maxsize="%s">%s</mo>`, size, size, html.EscapeString(d)), false, nil
+	}
+
+	switch name {
+	case "frac", "dfrac", "tfrac", "cfrac":
+		num, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		den, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		return "<mfrac>" + num + den + "</mfrac>", false, nil
+	case "binom":
+		n, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		k, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		return `<mrow><mo>(</mo><mfrac linethickness="0">` + n + k + `</mfrac><mo>)</mo></mrow>`, false, nil
+	case "sqrt":
+		return p.parseSqrt()
+	case "text", "textrm", "textit", "textbf", "mbox":
+		text, err := p.rawGroup()
+		if err != nil {
+			return "", false, err
+		}
+		return "<mtext>" + html.EscapeString(text) + "</mtext>", false, nil
+	case "operatorname":
+		text, err := p.rawGroup()
+		if err != nil {
+			return "", false, err
+		}
+		return "<mi>" + html.EscapeString(text) + "</mi>", false, nil
+	case "left":
+		return p.parseLeftRight()
+	case "right":
+		return "", false, fmt.Errorf("\\right без \\left")
+	case "begin":
+		env, err := p.rawGroup()
+		if err != nil {
+			return "", false, err
+		}
+		item, err := p.parseEnvironment(env)
+		return item, false, err
+	case "end":
+		return "", false, fmt.Errorf("\\end без \\begin")
+	}
+	return "", false, fmt.Errorf("неизвестная команда \\%s", name)
+}
+
+func (p *parser) parseSqrt() (string, bool, error) {
+	index := ""
+	if t := p.peek(); t.kind == tokChar && t.text == "[" {
+		p.next()
+		items, err := p.parseList(func(t token) bool { return t.kind == tokChar && t.text == "]" })
+		if err != nil {
+			return "", false, err
+		}
+		if err := p.expect(tokChar, "]"); err != nil {
+			return "", false, err
+		}
+		index = mrow(items)
+	}
+	arg, err := p.parseArgument()
+	if err != nil {
+		return "", false, err
+	}
+	if index != "" {
+		return "<mroot>" + arg + index + "</mroot>", false, nil
+	}
+	return "<msqrt>" + arg + "</msqrt>", false, nil
+}
+
+// rawGroup возвращает текст {группы} как есть, с пробелами
+func (p *parser) rawGroup() (string, error) {
+	if err := p.expect(tokOpen, "{"); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	depth := 0
+	for {
+		t := p.tokens[p.pos]
+		switch t.kind {
+		case tokEOF:
+			return "", fmt.Errorf("ожидалась }")
+		case tokOpen:
+			depth++
+		case tokClose:
+			if depth == 0 {
+				p.pos++
+				return b.String(), nil
+			}
+			depth--
+		}
+		b.WriteString(t.raw)
+		p.pos++
+	}
+}
+
+// delimiter читает скобку после \left, \right или \big
+func (p *parser) delimiter() (string, error) {
+	t := p.next()
+	switch t.kind {
+	case tokChar:
+		if t.text == "." {
+			return "", nil
+		}
+		return t.text, nil
+	case tokCommand:
+		if s, ok := operators[t.text]; ok {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("ожидалась скобка")
+}
+
+func (p *parser) parseLeftRight() (string, bool, error) {
+	left, err := p.delimiter()
+	if err != nil {
+		return "", false, err
+	}
+	items, err := p.parseList(func(t token) bool { return t.kind == tokCommand && t.text == "right" })
+	if err != nil {
+		return "", false, err
+	}
+	if t := p.next(); t.kind != tokCommand || t.text != "right" {
+		return "", false, fmt.Errorf("\\left без \\right")
+	}
+	right, err := p.delimiter()
+	if err != nil {
+		return "", false, err
+	}
+	return "<mrow>" + fence(left) + strings.Join(items, "") + fence(right) + "</mrow>", false, nil
+}
+
+func fence(d string) string {
+	if d == "" {
+		return ""
+	}
+	return `<mo fence="true" stretchy="true">` + html.EscapeString(d) + "</mo>"
+}
+
+func (p *parser) parseEnvironment(name string) (string, error) {
+	env, ok := environments[name]
+	if !ok {
+		return "", fmt.Errorf("неизвестное окружение %s", name)
+	}
+	if name == "array" {
+		// Описание колонок {lcr} не влияет на MathML
+		if _, err := p.rawGroup(); err != nil {
+			return "", err
+		}
+	}
+
+	stop := func(t token) bool {
+		return t.kind == tokAmp || t.kind == tokNewline || t.kind == tokCommand && t.text == "end"
+	}
+	var rows [][]string
+	var row []string
+	for {
+		items, err := p.parseList(stop)
+		if err != nil {
+			return "", err
+		}
+		row = append(row, mrow(items))
+
+		t := p.next()
+		switch {
+		case t.kind == tokAmp:
+			continue
+		case t.kind == tokNewline:
+			rows = append(rows, row)
+			row = nil
+			continue
+		case t.kind == tokCommand && t.text == "end":
+			end, err := p.rawGroup()
+			if err != nil {
+				return "", err
+			}
+			if end != name {
+				return "", fmt.Errorf("ожидался \\end{%s}", name)
+			}
+		default:
+			return "", fmt.Errorf("ожидался \\end{%s}", name)
+		}
+		break
+	}
+	// Завершающий \\ не создаёт пустую строку
+	if len(row) > 1 || len(row) == 1 && row[0] != "<mrow></mrow>" {
+		rows = append(rows, row)
+	}
+
+	var b strings.Builder
+	if env.open != "" || env.close != "" {
+		b.WriteString("<mrow>" + fence(env.open))
+	}
+	if env.align != "" {
+		fmt.Fprintf(&b, `<mtable columnalign="%s">`, env.align)
+	} else {
+		b.WriteString("<mtable>")
+	}
+	for _, row := range rows {
+		b.WriteString("<mtr>")
+		for _, cell := range row {
+			b.WriteString("<mtd>" + cell + "</mtd>")
+		}
+		b.WriteString("</mtr>")
+	}
+	b.WriteString("</mtable>")
+	if env.open != "" || env.close != "" {
+		b.WriteString(fence(env.close) + "</mrow>")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,81 @@
+package page
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+)
+
+var (
+	//go:embed templates/layout.html
+	layoutHTML string
+	//go:embed templates/style.css
+	defaultStyle string
+	//go:embed templates/copy.js
+	copyJS string
+	//go:embed templates/redirect.html
+	redirectHTML string
+
+	layout   = template.Must(template.New("layout").Parse(layoutHTML))
+	redirect = template.Must(template.New("redirect").Parse(redirectHTML))
+)
+
+// CopyButtonScript добавляет к блокам кода кнопку «Копировать»; подключается в Head
+var CopyButtonScript = template.HTML("<script>\n" + copyJS + "</script>\n")
+
+type Data struct {
+	Title string
+	// Готовый HTML содержимого страницы
+	Body template.HTML
+	// Дополнительные теги для <head>
+	Head template.HTML
+	// Не выводить Title заголовком h1, например когда у содержимого есть свой
+	HideHeading bool
+	// Оглавление перед содержимым; пустое не выводится
+	TOC []TOCItem
+}
+
+// TOCItem — пункт оглавления со ссылкой на заголовок и вложенными подзаголовками
+type TOCItem struct {
+	Title    string
+	ID       string
+	Children []TOCItem
+}
+
+func Render(w io.Writer, data Data) error {
+	err := layout.Execute(w, struct {
+		Data
+		Style template.CSS
+	}{
+		Data:  data,
+		Style: template.CSS(defaultStyle),
+	})
+	if err != nil {
+		return fmt.Errorf("не удалось отрисовать страницу: %v", err)
+	}
+	return nil
+}
+
+// TOC отрисовывает оглавление так же, как его выводит страница
+func TOC(items []TOCItem) (template.HTML, error) {
+	var buf bytes.Buffer
+	if err := layout.ExecuteTemplate(&buf, "toc", items); err != nil {
+		return "", fmt.Errorf("не удалось отрисовать оглавление: %v", err)
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// Redirect отрисовывает страницу, которая сразу перенаправляет на url, например со старого имени заметки.
+// url должен быть уже экранирован
+func Redirect(w io.Writer, title, url string) error {
+	err := redirect.Execute(w, struct {
+		Title string
+		URL   string
+	}{title, url})
+	if err != nil {
+		return fmt.Errorf("не удалось отрисовать страницу перенаправления: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package page
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Render(&buf, Data{
+		Title: "Заметка <1>",
+		Body:  "<p>Текст</p>",
+	})
+
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "<title>Заметка &lt;1&gt;</title>")
+	require.Contains(t, buf.String(), "<p>Текст</p>")
+	require.Contains(t, buf.String(), ".page {")
+}
+
+func TestRender_HideHeading(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Render(&buf, Data{Title: "Заметка", Body: "<h1>Своя</h1>", HideHeading: true})
+
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "<title>Заметка</title>")
+	require.NotContains(t, buf.String(), `class="page-title"`)
+}
+
+func TestRender_TOC(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Render(&buf, Data{Title: "Заметка", TOC: []TOCItem{
+		{Title: "Планы", ID: "планы", Children: []TOCItem{{Title: "Утро & вечер", ID: "утро-вечер"}}},
+		{Title: "Итоги", ID: "итоги"},
+	}})
+
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), `<nav class="toc">`)
+	require.Contains(t, buf.String(), `<li><a href="#%d0%bf%d0%bb%d0%b0%d0%bd%d1%8b">Планы</a>`)
+	require.Contains(t, buf.String(), `<ul>
+<li><a href="#%d1%83%d1%82%d1%80%d0%be-%d0%b2%d0%b5%d1%87%d0%b5%d1%80">Утро &amp; вечер</a></li>
+</ul>
+</li>`)
+}
+
+func TestRender_NoTOC(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, Render(&buf, Data{Title: "Заметка"}))
+	require.NotContains(t, buf.String(), `class="toc"`)
+}
+
+func TestRedirect(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, Redirect(&buf, "Новая <заметка>", "new%20note.html"))
+	require.Contains(t, buf.String(), `<meta http-equiv="refresh" content="0; url=new%20note.html">`)
+	require.Contains(t, buf.String(), `<a href="new%20note.html">Новая &lt;заметка&gt;</a>`)
+}
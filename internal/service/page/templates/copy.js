@@ -0,0 +1,20 @@
+document.addEventListener("DOMContentLoaded", function () {
+  document.querySelectorAll("pre").forEach(function (pre) {
+    var button = document.createElement("button");
+    button.type = "button";
+    button.className = "copy-button";
+    button.textContent = "Копировать";
+    button.addEventListener("click", function () {
+      // Номера строк подсветки не копируются
+      var lines = pre.querySelectorAll(".cl");
+      var text = lines.length
+        ? Array.prototype.map.call(lines, function (l) { return l.textContent; }).join("")
+        : pre.textContent;
+      navigator.clipboard.writeText(text).then(function () {
+        button.textContent = "Скопировано";
+        setTimeout(function () { button.textContent = "Копировать"; }, 1500);
+      });
+    });
+    pre.appendChild(button);
+  });
+});
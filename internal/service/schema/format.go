@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+func Write(w io.Writer, violations []Violation, format string) error {
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return WriteText(w, violations)
+	case FormatJSON:
+		return WriteJSON(w, violations)
+	default:
+		return fmt.Errorf("неизвестный формат отчёта: %s", format)
+	}
+}
+
+func WriteJSON(w io.Writer, violations []Violation) error {
+	if violations == nil {
+		violations = []Violation{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(violations)
+}
+
+func WriteText(w io.Writer, violations []Violation) error {
+	var b strings.Builder
+	errors := 0
+	for _, v := range violations {
+		fmt.Fprintf(&b, "%s:%d: %s: %s\n", v.File, v.Line, v.Severity, v.Message)
+		if v.Severity == SeverityError {
+			errors++
+		}
+	}
+	if len(violations) == 0 {
+		b.WriteString("Нарушений не найдено\n")
+	} else {
+		fmt.Fprintf(&b, "Ошибок: %d, предупреждений: %d\n", errors, len(violations)-errors)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
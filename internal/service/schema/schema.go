@@ -0,0 +1,266 @@
+package schema
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Типы значений полей FrontMatter
+const (
+	TypeString = "string"
+	TypeDate   = "date"
+	TypeBool   = "bool"
+	TypeNumber = "number"
+	TypeList   = "list"
+)
+
+type Rule struct {
+	Field    string
+	Required bool
+	// Тип значения; пусто — любой
+	Type string
+	// Регулярное выражение для строки или каждого элемента списка
+	Pattern string
+	// Дата должна совпадать с датой в имени файла, если она там есть
+	MatchFilename bool
+	// error (по умолчанию) или warning
+	Severity string
+	// Регулярное выражение для пути заметки относительно корня; пусто — все заметки
+	Path string
+}
+
+type Violation struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Field    string `json:"field,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type rule struct {
+	Rule
+	pattern *regexp.Regexp
+	path    *regexp.Regexp
+}
+
+type Validator struct {
+	rules []rule
+}
+
+// New проверяет правила и компилирует их регулярные выражения
+func New(rules []Rule) (*Validator, error) {
+	v := &Validator{}
+	for i, r := range rules {
+		if r.Field == "" {
+			return nil, fmt.Errorf("правило %d: не задано поле", i+1)
+		}
+		switch r.Type {
+		case "", TypeString, TypeDate, TypeBool, TypeNumber, TypeList:
+		default:
+			return nil, fmt.Errorf("правило для %s: неизвестный тип %s", r.Field, r.Type)
+		}
+		switch r.Severity {
+		case "":
+			r.Severity = SeverityError
+		case SeverityError, SeverityWarning:
+		default:
+			return nil, fmt.Errorf("правило для %s: неизвестный уровень %s", r.Field, r.Severity)
+		}
+
+		compiled := rule{Rule: r}
+		var err error
+		if r.Pattern != "" {
+			if compiled.pattern, err = regexp.Compile(r.Pattern); err != nil {
+				return nil, fmt.Errorf("правило для %s: некорректный pattern: %v", r.Field, err)
+			}
+		}
+		if r.Path != "" {
+			if compiled.path, err = regexp.Compile(r.Path); err != nil {
+				return nil, fmt.Errorf("правило для %s: некорректный path: %v", r.Field, err)
+			}
+		}
+		v.rules = append(v.rules, compiled)
+	}
+	return v, nil
+}
+
+// ValidateDir проверяет FrontMatter всех заметок в root
+func (v *Validator) ValidateDir(root string) ([]Violation, error) {
+	violations := []Violation{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.ToLower(filepath.Ext(path)) != ".md" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("не удалось прочитать файл %s: %v", path, err)
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, v.Validate(filepath.ToSlash(rel), content)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations, nil
+}
+
+// Validate проверяет FrontMatter одной заметки; relPath используется в отчёте и для правил path
+func (v *Validator) Validate(relPath string, content []byte) []Violation {
+	node, offset, err := vault.FrontMatterNode(content)
+	if err != nil {
+		return []Violation{{File: relPath, Line: 1, Severity: SeverityError, Message: err.Error()}}
+	}
+
+	// Ключи и значения в MappingNode идут парами
+	fields := make(map[string][2]*yaml.Node)
+	if node != nil && node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			fields[node.Content[i].Value] = [2]*yaml.Node{node.Content[i], node.Content[i+1]}
+		}
+	}
+	// Отсутствующее поле отмечается на первой строке FrontMatter
+	startLine := offset + 1
+
+	name := strings.TrimSuffix(filepath.Base(relPath), filepath.Ext(relPath))
+	var violations []Violation
+	for _, r := range v.rules {
+		if r.path != nil && !r.path.MatchString(relPath) {
+			continue
+		}
+		report := func(line int, format string, args ...any) {
+			violations = append(violations, Violation{
+				File:     relPath,
+				Line:     line,
+				Field:    r.Field,
+				Severity: r.Severity,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+
+		pair, ok := fields[r.Field]
+		if !ok || isEmpty(pair[1]) {
+			if r.Required {
+				line := startLine
+				if ok {
+					line = offset + pair[0].Line
+				}
+				report(line, "обязательное поле %s не заполнено", r.Field)
+			}
+			continue
+		}
+		value := pair[1]
+		line := offset + value.Line
+
+		if msg := checkType(value, r.Type); msg != "" {
+			report(line, "поле %s: %s", r.Field, msg)
+			continue
+		}
+		if r.pattern != nil {
+			for _, item := range scalars(value) {
+				if !r.pattern.MatchString(item.Value) {
+					report(offset+item.Line, "поле %s: значение %q не соответствует шаблону %s", r.Field, item.Value, r.Pattern)
+				}
+			}
+		}
+		if r.MatchFilename && value.Kind == yaml.ScalarNode {
+			if fileDate, ok := vault.DateFromName(name, time.UTC); ok {
+				if date, err := vault.ParseDate(value.Value, time.UTC); err == nil && date.Format(vault.DateLayout) != fileDate.Format(vault.DateLayout) {
+					report(line, "поле %s: дата %s не совпадает с датой в имени файла %s", r.Field, value.Value, fileDate.Format(vault.DateLayout))
+				}
+			}
+		}
+	}
+	return violations
+}
+
+func isEmpty(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Tag == "!!null" || node.Value == ""
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
+
+// checkType возвращает описание несоответствия типу или пустую строку
+func checkType(node *yaml.Node, typ string) string {
+	switch typ {
+	case TypeString:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!str" {
+			return "ожидается строка"
+		}
+	case TypeBool:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			return fmt.Sprintf("ожидается true или false, получено %q", node.Value)
+		}
+	case TypeNumber:
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") {
+			return fmt.Sprintf("ожидается число, получено %q", node.Value)
+		}
+	case TypeDate:
+		if node.Kind != yaml.ScalarNode {
+			return "ожидается дата"
+		}
+		// Принимаются те же форматы, что и при разборе заметок, в том числе вывод fix
+		if _, err := vault.ParseDate(node.Value, time.UTC); err != nil {
+			return fmt.Sprintf("ожидается дата ГГГГ-ММ-ДД, ДД.ММ.ГГГГ или RFC 3339, получено %q", node.Value)
+		}
+	case TypeList:
+		if node.Kind != yaml.SequenceNode {
+			return "ожидается список"
+		}
+	}
+	return ""
+}
+
+// scalars возвращает строковые значения узла: сам скаляр или элементы списка
+func scalars(node *yaml.Node) []*yaml.Node {
+	if node.Kind == yaml.ScalarNode {
+		return []*yaml.Node{node}
+	}
+	var items []*yaml.Node
+	for _, item := range node.Content {
+		if item.Kind == yaml.ScalarNode {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// HasErrors сообщает, есть ли среди нарушений ошибки, а не только предупреждения
+func HasErrors(violations []Violation) bool {
+	for _, v := range violations {
+		if v.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
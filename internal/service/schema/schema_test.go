@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var dailyRules = []Rule{
+	{Field: "date", Required: true, Type: TypeDate, MatchFilename: true, Path: `\d{4}-\d{2}-\d{2}\.md$`},
+	{Field: "author", Required: true, Type: TypeString, Severity: SeverityWarning},
+	{Field: "tags", Type: TypeList, Pattern: "^#", Severity: SeverityWarning},
+	{Field: "closed", Type: TypeBool},
+}
+
+func TestValidate(t *testing.T) {
+	v, err := New(dailyRules)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		relPath  string
+		content  string
+		expected []Violation
+	}{
+		{
+			name:    "Корректная заметка",
+			relPath: "daily/2024-12-09.md",
+			content: "---\ndate: 2024-12-09\nauthor: Иван\ntags: ['#daily']\nclosed: true\n---\n",
+		},
+		{
+			name:    "Нарушения",
+			relPath: "daily/2024-12-09.md",
+			content: "---\ndate: 2024-12-10\nauthor:\ntags:\n  - '#daily'\n  - go\nclosed: yes\n---\n",
+			expected: []Violation{
+				{File: "daily/2024-12-09.md", Line: 2, Field: "date", Severity: SeverityError, Message: "поле date: дата 2024-12-10 не совпадает с датой в имени файла 2024-12-09"},
+				{File: "daily/2024-12-09.md", Line: 3, Field: "author", Severity: SeverityWarning, Message: "обязательное поле author не заполнено"},
+				{File: "daily/2024-12-09.md", Line: 6, Field: "tags", Severity: SeverityWarning, Message: `поле tags: значение "go" не соответствует шаблону ^#`},
+				{File: "daily/2024-12-09.md", Line: 7, Field: "closed", Severity: SeverityError, Message: `поле closed: ожидается true или false, получено "yes"`},
+			},
+		},
+		{
+			name:    "Неверная дата и тип",
+			relPath: "2024-12-09.md",
+			content: "---\ndate: 9 декабря\nauthor: 42\ntags: '#daily'\n---\n",
+			expected: []Violation{
+				{File: "2024-12-09.md", Line: 2, Field: "date", Severity: SeverityError, Message: `поле date: ожидается дата ГГГГ-ММ-ДД, ДД.ММ.ГГГГ или RFC 3339, получено "9 декабря"`},
+				{File: "2024-12-09.md", Line: 3, Field: "author", Severity: SeverityWarning, Message: "поле author: ожидается строка"},
+				{File: "2024-12-09.md", Line: 4, Field: "tags", Severity: SeverityWarning, Message: "поле tags: ожидается список"},
+			},
+		},
+		{
+			name:    "Без FrontMatter, правило date не для этого пути",
+			relPath: "ideas/Идея.md",
+			content: "# Идея",
+			expected: []Violation{
+				{File: "ideas/Идея.md", Line: 1, Field: "author", Severity: SeverityWarning, Message: "обязательное поле author не заполнено"},
+			},
+		},
+		{
+			name:    "Некорректный YAML",
+			relPath: "a.md",
+			content: "---\ndate: [\n---\n",
+			expected: []Violation{
+				{File: "a.md", Line: 1, Severity: SeverityError, Message: "не удалось распарсить FrontMatter: yaml: line 2: did not find expected node content"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, v.Validate(tc.relPath, []byte(tc.content)))
+		})
+	}
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	for _, rules := range [][]Rule{
+		{{Type: TypeString}},
+		{{Field: "date", Type: "timestamp"}},
+		{{Field: "date", Severity: "fatal"}},
+		{{Field: "tags", Pattern: "["}},
+	} {
+		_, err := New(rules)
+		require.Error(t, err)
+	}
+}
+
+func TestValidateDir(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "daily"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "daily", "2024-12-09.md"), []byte("---\ndate: 2024-12-09\nauthor: Иван\n---\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "daily", "2024-12-10.md"), []byte("---\nauthor: Иван\n---\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "b.md"), []byte("---\nauthor: Иван\nclosed: нет\n---\n"), 0644))
+
+	v, err := New(dailyRules)
+	require.NoError(t, err)
+	violations, err := v.ValidateDir(root)
+
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+	require.Equal(t, "b.md", violations[0].File)
+	require.Equal(t, "daily/2024-12-10.md", violations[1].File)
+	require.Equal(t, "обязательное поле date не заполнено", violations[1].Message)
+	require.True(t, HasErrors(violations))
+	require.False(t, HasErrors([]Violation{{Severity: SeverityWarning}}))
+}
+
+func TestWrite(t *testing.T) {
+	violations := []Violation{
+		{File: "a.md", Line: 2, Field: "date", Severity: SeverityError, Message: "ошибка"},
+		{File: "a.md", Line: 3, Field: "author", Severity: SeverityWarning, Message: "предупреждение"},
+	}
+
+	var text bytes.Buffer
+	require.NoError(t, Write(&text, violations, FormatText))
+	require.Equal(t, "a.md:2: error: ошибка\na.md:3: warning: предупреждение\nОшибок: 1, предупреждений: 1\n", text.String())
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, nil, FormatJSON))
+	require.Equal(t, "[]\n", out.String())
+
+	require.Error(t, Write(&out, nil, "csv"))
+}
@@ -0,0 +1,171 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	FileName    = "sitemap.xml"
+	RobotsFile  = "robots.txt"
+	xmlns       = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	partPattern = "sitemap-%d.xml"
+	// Шаблон имён частей для поиска файлов, оставшихся от прежних сборок
+	PartGlob = "sitemap-*.xml"
+	// Ограничение протокола sitemaps.org на число адресов в одном файле
+	MaxURLs = 50000
+)
+
+type URL struct {
+	// Абсолютный адрес страницы
+	Loc     string
+	LastMod time.Time
+}
+
+// File — файл карты сайта с путём относительно корня сайта
+type File struct {
+	Name    string
+	Content []byte
+}
+
+type urlSet struct {
+	XMLName xml.Name   `xml:"urlset"`
+	NS      string     `xml:"xmlns,attr"`
+	URLs    []urlEntry `xml:"url"`
+}
+
+type urlEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name   `xml:"sitemapindex"`
+	NS       string     `xml:"xmlns,attr"`
+	Sitemaps []urlEntry `xml:"sitemap"`
+}
+
+// Build возвращает sitemap.xml, а если адресов больше MaxURLs — индекс sitemap.xml и части sitemap-N.xml.
+// absURL превращает имя файла части в абсолютный адрес для индекса
+func Build(urls []URL, absURL func(name string) string) ([]File, error) {
+	return build(urls, absURL, MaxURLs)
+}
+
+func build(urls []URL, absURL func(name string) string, limit int) ([]File, error) {
+	if len(urls) <= limit {
+		content, err := encode(urlSet{NS: xmlns, URLs: entries(urls)})
+		if err != nil {
+			return nil, err
+		}
+		return []File{{Name: FileName, Content: content}}, nil
+	}
+
+	var files []File
+	index := sitemapIndex{NS: xmlns}
+	for start := 0; start < len(urls); start += limit {
+		part := urls[start:min(start+limit, len(urls))]
+		name := fmt.Sprintf(partPattern, len(files)+1)
+		content, err := encode(urlSet{NS: xmlns, URLs: entries(part)})
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: name, Content: content})
+
+		var latest time.Time
+		for _, u := range part {
+			if u.LastMod.After(latest) {
+				latest = u.LastMod
+			}
+		}
+		index.Sitemaps = append(index.Sitemaps, urlEntry{Loc: absURL(name), LastMod: lastMod(latest)})
+	}
+
+	content, err := encode(index)
+	if err != nil {
+		return nil, err
+	}
+	return append([]File{{Name: FileName, Content: content}}, files...), nil
+}
+
+func entries(urls []URL) []urlEntry {
+	result := make([]urlEntry, len(urls))
+	for i, u := range urls {
+		result[i] = urlEntry{Loc: u.Loc, LastMod: lastMod(u.LastMod)}
+	}
+	return result
+}
+
+// lastMod форматирует дату в W3C Datetime: дата без времени остаётся датой
+func lastMod(t time.Time) string {
+	switch {
+	case t.IsZero():
+		return ""
+	case t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0:
+		return t.Format("2006-01-02")
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
+
+func encode(doc any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("не удалось записать карту сайта: %v", err)
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// RobotsRule — группа правил robots.txt для одного User-agent
+type RobotsRule struct {
+	UserAgent string
+	Allow     []string
+	Disallow  []string
+}
+
+// Robots возвращает robots.txt; без правил разрешено всё. sitemapURL пусто — строка Sitemap не пишется.
+// Пути правил задаются от корня сайта и дополняются basePath — путём, по которому сайт опубликован на хосте
+func Robots(rules []RobotsRule, basePath, sitemapURL string) []byte {
+	if len(rules) == 0 {
+		rules = []RobotsRule{{UserAgent: "*"}}
+	}
+
+	var b strings.Builder
+	for i, rule := range rules {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		agent := rule.UserAgent
+		if agent == "" {
+			agent = "*"
+		}
+		fmt.Fprintf(&b, "User-agent: %s\n", agent)
+		for _, path := range rule.Allow {
+			fmt.Fprintf(&b, "Allow: %s\n", withBase(basePath, path))
+		}
+		for _, path := range rule.Disallow {
+			fmt.Fprintf(&b, "Disallow: %s\n", withBase(basePath, path))
+		}
+		// Пустой Disallow означает, что ограничений нет
+		if len(rule.Allow) == 0 && len(rule.Disallow) == 0 {
+			b.WriteString("Disallow:\n")
+		}
+	}
+	if sitemapURL != "" {
+		fmt.Fprintf(&b, "\nSitemap: %s\n", sitemapURL)
+	}
+	return []byte(b.String())
+}
+
+func withBase(basePath, path string) string {
+	if basePath == "" || !strings.HasPrefix(path, "/") {
+		return path
+	}
+	return strings.TrimSuffix(basePath, "/") + path
+}
@@ -0,0 +1,79 @@
+package sitemap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func absURL(name string) string {
+	return "https://example.com/" + name
+}
+
+func TestBuild(t *testing.T) {
+	files, err := Build([]URL{
+		{Loc: "https://example.com/a.html", LastMod: time.Date(2024, 12, 9, 0, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/b.html?x=1&y=2", LastMod: time.Date(2024, 12, 10, 8, 30, 0, 0, time.UTC)},
+		{Loc: "https://example.com/c.html"},
+	}, absURL)
+
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, FileName, files[0].Name)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/a.html</loc>
+    <lastmod>2024-12-09</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/b.html?x=1&amp;y=2</loc>
+    <lastmod>2024-12-10T08:30:00Z</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/c.html</loc>
+  </url>
+</urlset>
+`, string(files[0].Content))
+}
+
+func TestBuild_Index(t *testing.T) {
+	urls := []URL{
+		{Loc: "https://example.com/1.html", LastMod: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/2.html", LastMod: time.Date(2024, 12, 3, 0, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/3.html", LastMod: time.Date(2024, 12, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	files, err := build(urls, absURL, 2)
+
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	require.Equal(t, []string{"sitemap.xml", "sitemap-1.xml", "sitemap-2.xml"}, []string{files[0].Name, files[1].Name, files[2].Name})
+	require.Contains(t, string(files[0].Content), `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>https://example.com/sitemap-1.xml</loc>
+    <lastmod>2024-12-03</lastmod>
+  </sitemap>
+  <sitemap>
+    <loc>https://example.com/sitemap-2.xml</loc>
+    <lastmod>2024-12-02</lastmod>
+  </sitemap>
+</sitemapindex>`)
+	require.Contains(t, string(files[1].Content), "2.html")
+	require.NotContains(t, string(files[1].Content), "3.html")
+	require.Contains(t, string(files[2].Content), "3.html")
+}
+
+func TestRobots(t *testing.T) {
+	require.Equal(t, "User-agent: *\nDisallow:\n", string(Robots(nil, "", "")))
+
+	require.Equal(t, "User-agent: *\nAllow: /public/\nDisallow: /private/\n\nUser-agent: BadBot\nDisallow: /\n\nSitemap: https://example.com/sitemap.xml\n",
+		string(Robots([]RobotsRule{
+			{Allow: []string{"/public/"}, Disallow: []string{"/private/"}},
+			{UserAgent: "BadBot", Disallow: []string{"/"}},
+		}, "", "https://example.com/sitemap.xml")))
+
+	require.Equal(t, "User-agent: *\nAllow: /daily/public/\nDisallow: /daily/tasks.json\n",
+		string(Robots([]RobotsRule{{Allow: []string{"/public/"}, Disallow: []string{"/tasks.json"}}}, "/daily", "")))
+}
@@ -0,0 +1,52 @@
+package tasks
+
+import (
+	"bytes"
+	"html/template"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/page"
+)
+
+var overviewTemplate = template.Must(template.New("tasks").Parse(`
+{{- if not .Groups}}<p>Открытых задач нет</p>{{end}}
+{{- range .Groups}}
+<h2>{{.Title}}</h2>
+<ul class="task-list">
+{{- range .Entries}}
+<li class="task-list-item{{if .Overdue}} task-overdue{{end}}">
+<input type="checkbox" disabled{{if eq .Status "in_progress"}} class="task-in-progress"{{end}}> {{.Text}}
+{{- if .Priority}} <span class="task-priority task-priority-{{.Priority}}">{{.Priority}}</span>{{end}}
+{{- if .Due}} <span class="task-due">📅 {{.Due}}</span>{{end}}
+<a class="task-note" href="{{call $.Href .Path}}">{{.Note}}</a>
+</li>
+{{- end}}
+</ul>
+{{- end}}
+`))
+
+// BuildPage собирает страницу с открытыми задачами.
+// href превращает относительный путь заметки в ссылку на её HTML-файл
+func BuildPage(entries []Entry, today time.Time, href func(relPath string) string) ([]byte, error) {
+	var body bytes.Buffer
+	err := overviewTemplate.Execute(&body, struct {
+		Groups []Group
+		Href   func(string) string
+	}{
+		Groups: GroupOpen(entries, today),
+		Href:   href,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = page.Render(&buf, page.Data{
+		Title: "Задачи",
+		Body:  template.HTML(body.String()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
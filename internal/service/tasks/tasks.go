@@ -0,0 +1,111 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+const (
+	PageFileName = "tasks.html"
+	JSONFileName = "tasks.json"
+)
+
+// Entry — задача вместе с заметкой, в которой она найдена
+type Entry struct {
+	vault.Task
+	Note     string `json:"note"`
+	Path     string `json:"path"`
+	NoteDate string `json:"note_date,omitempty"`
+	Overdue  bool   `json:"overdue"`
+}
+
+type Group struct {
+	Title   string
+	Entries []Entry
+}
+
+// Collect собирает задачи всех заметок хранилища в порядке следования заметок
+func Collect(v *vault.Vault, today time.Time) []Entry {
+	todayKey := today.Format(vault.DateLayout)
+
+	entries := []Entry{}
+	for _, note := range v.Notes {
+		noteDate := ""
+		if date, ok := note.Date(); ok {
+			noteDate = date.Format(vault.DateLayout)
+		}
+		for _, task := range note.Tasks {
+			entries = append(entries, Entry{
+				Task:     task,
+				Note:     note.Name,
+				Path:     note.RelPath,
+				NoteDate: noteDate,
+				Overdue:  task.IsOpen() && task.Due != "" && task.Due < todayKey,
+			})
+		}
+	}
+	return entries
+}
+
+// GroupOpen группирует открытые задачи: просроченные, на сегодня, по будущим срокам и без срока
+func GroupOpen(entries []Entry, today time.Time) []Group {
+	todayKey := today.Format(vault.DateLayout)
+
+	var overdue, dueToday, noDue []Entry
+	upcoming := make(map[string][]Entry)
+	for _, e := range entries {
+		switch {
+		case !e.IsOpen():
+			continue
+		case e.Due == "":
+			noDue = append(noDue, e)
+		case e.Overdue:
+			overdue = append(overdue, e)
+		case e.Due == todayKey:
+			dueToday = append(dueToday, e)
+		default:
+			upcoming[e.Due] = append(upcoming[e.Due], e)
+		}
+	}
+
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].Due < overdue[j].Due
+	})
+
+	var groups []Group
+	add := func(title string, entries []Entry) {
+		if len(entries) > 0 {
+			groups = append(groups, Group{Title: title, Entries: entries})
+		}
+	}
+
+	add("Просрочено", overdue)
+	add("Сегодня", dueToday)
+
+	dates := make([]string, 0, len(upcoming))
+	for date := range upcoming {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, date := range dates {
+		add(date, upcoming[date])
+	}
+
+	add("Без срока", noDue)
+
+	return groups
+}
+
+func WriteJSON(w io.Writer, entries []Entry) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(entries); err != nil {
+		return fmt.Errorf("не удалось записать задачи в JSON: %v", err)
+	}
+	return nil
+}
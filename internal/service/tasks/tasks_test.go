@@ -0,0 +1,90 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+var today = time.Date(2024, 12, 10, 9, 0, 0, 0, time.UTC)
+
+func testVault() *vault.Vault {
+	return vault.New("", []*vault.Note{
+		{
+			Name:        "2024-12-09",
+			RelPath:     "2024-12-09.md",
+			FrontMatter: &vault.FrontMatter{Date: "2024-12-09"},
+			Tasks: []vault.Task{
+				{Text: "просрочена", Status: vault.TaskOpen, Due: "2024-12-01", Line: 5},
+				{Text: "сегодня", Status: vault.TaskOpen, Due: "2024-12-10", Line: 6},
+				{Text: "выполнена", Status: vault.TaskDone, Due: "2024-12-01", Line: 7},
+			},
+		},
+		{
+			Name:        "Alpha",
+			RelPath:     "projects/Alpha.md",
+			FrontMatter: &vault.FrontMatter{},
+			Tasks: []vault.Task{
+				{Text: "потом", Status: vault.TaskInProgress, Due: "2024-12-20", Line: 1},
+				{Text: "когда-нибудь", Status: vault.TaskOpen, Line: 2},
+			},
+		},
+	})
+}
+
+func TestCollect(t *testing.T) {
+	entries := Collect(testVault(), today)
+
+	require.Len(t, entries, 5)
+	require.Equal(t, "2024-12-09", entries[0].Note)
+	require.Equal(t, "2024-12-09", entries[0].NoteDate)
+	require.True(t, entries[0].Overdue)
+	require.False(t, entries[1].Overdue)
+	require.False(t, entries[2].Overdue, "выполненная задача не может быть просрочена")
+	require.Equal(t, "projects/Alpha.md", entries[3].Path)
+	require.Empty(t, entries[3].NoteDate)
+}
+
+func TestGroupOpen(t *testing.T) {
+	groups := GroupOpen(Collect(testVault(), today), today)
+
+	titles := make([]string, len(groups))
+	for i, g := range groups {
+		titles[i] = g.Title
+	}
+	require.Equal(t, []string{"Просрочено", "Сегодня", "2024-12-20", "Без срока"}, titles)
+	require.Equal(t, "просрочена", groups[0].Entries[0].Text)
+	require.Len(t, groups[0].Entries, 1)
+}
+
+func TestBuildPage(t *testing.T) {
+	content, err := BuildPage(Collect(testVault(), today), today, func(relPath string) string {
+		return "/" + relPath
+	})
+	require.NoError(t, err)
+
+	html := string(content)
+	require.Contains(t, html, "<h2>Просрочено</h2>")
+	require.Contains(t, html, `class="task-list-item task-overdue"`)
+	require.Contains(t, html, `<a class="task-note" href="/projects/Alpha.md">Alpha</a>`)
+	require.NotContains(t, html, "выполнена")
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteJSON(&buf, Collect(testVault(), today))
+	require.NoError(t, err)
+
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded, 5)
+	require.Equal(t, "просрочена", decoded[0]["text"])
+	require.Equal(t, "2024-12-01", decoded[0]["due"])
+	require.Equal(t, true, decoded[0]["overdue"])
+	require.Equal(t, "done", decoded[2]["status"])
+}
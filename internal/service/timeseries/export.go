@@ -0,0 +1,124 @@
+package timeseries
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+func WriteCSV(w io.Writer, t *Table) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(append([]string{"date"}, t.Fields...)); err != nil {
+		return fmt.Errorf("не удалось записать CSV: %v", err)
+	}
+	for _, row := range t.Rows {
+		record := []string{row.Date}
+		for _, field := range t.Fields {
+			value, ok := row.Values[field]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("не удалось записать CSV: %v", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("не удалось записать CSV: %v", err)
+	}
+	return nil
+}
+
+// WriteJSONL пишет по объекту на строку; пропущенные значения записываются как null
+func WriteJSONL(w io.Writer, t *Table) error {
+	enc := json.NewEncoder(w)
+	for _, row := range t.Rows {
+		record := make(map[string]any, len(t.Fields)+1)
+		record["date"] = row.Date
+		for _, field := range t.Fields {
+			if value, ok := row.Values[field]; ok {
+				record[field] = value
+			} else {
+				record[field] = nil
+			}
+		}
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("не удалось записать JSON Lines: %v", err)
+		}
+	}
+	return nil
+}
+
+// WriteSQLite пересоздаёт таблицу daily_fields в базе по пути path:
+// колонка date и по колонке REAL на каждое поле
+func WriteSQLite(path string, t *Table) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть базу SQLite: %v", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("не удалось начать транзакцию: %v", err)
+	}
+	defer tx.Rollback()
+
+	columns := []string{quoteIdent("date") + " TEXT PRIMARY KEY"}
+	names := []string{quoteIdent("date")}
+	for _, field := range t.Fields {
+		columns = append(columns, quoteIdent(field)+" REAL")
+		names = append(names, quoteIdent(field))
+	}
+
+	statements := []string{
+		"DROP TABLE IF EXISTS daily_fields",
+		fmt.Sprintf("CREATE TABLE daily_fields (%s)", strings.Join(columns, ", ")),
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("не удалось создать таблицу daily_fields: %v", err)
+		}
+	}
+
+	insert, err := tx.Prepare(fmt.Sprintf("INSERT INTO daily_fields (%s) VALUES (%s)",
+		strings.Join(names, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")))
+	if err != nil {
+		return fmt.Errorf("не удалось подготовить запрос: %v", err)
+	}
+	defer insert.Close()
+
+	for _, row := range t.Rows {
+		args := []any{row.Date}
+		for _, field := range t.Fields {
+			if value, ok := row.Values[field]; ok {
+				args = append(args, value)
+			} else {
+				args = append(args, nil)
+			}
+		}
+		if _, err := insert.Exec(args...); err != nil {
+			return fmt.Errorf("не удалось записать строку за %s: %v", row.Date, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("не удалось сохранить изменения: %v", err)
+	}
+	return nil
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,65 @@
+package timeseries
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteCSV(&buf, Build(testVault(), true))
+
+	require.NoError(t, err)
+	require.Equal(t, `date,mood,sleep,steps
+2024-12-09,6,6.5,
+2024-12-10,,,
+2024-12-11,,,
+2024-12-12,,,10000
+`, buf.String())
+}
+
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteJSONL(&buf, Build(testVault(), false))
+
+	require.NoError(t, err)
+	require.Equal(t, `{"date":"2024-12-09","mood":6,"sleep":6.5,"steps":null}
+{"date":"2024-12-12","mood":null,"sleep":null,"steps":10000}
+`, buf.String())
+}
+
+func TestWriteSQLite(t *testing.T) {
+	dir, err := os.MkdirTemp("", "timeseries")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	dbPath := filepath.Join(dir, "vault.db")
+
+	// Повторный экспорт пересоздаёт таблицу, а не дублирует строки
+	require.NoError(t, WriteSQLite(dbPath, Build(testVault(), true)))
+	require.NoError(t, WriteSQLite(dbPath, Build(testVault(), true)))
+
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM daily_fields").Scan(&count))
+	require.Equal(t, 4, count)
+
+	var mood sql.NullFloat64
+	var sleep float64
+	require.NoError(t, db.QueryRow(`SELECT mood, sleep FROM daily_fields WHERE date = '2024-12-09'`).Scan(&mood, &sleep))
+	require.Equal(t, 6.0, mood.Float64)
+	require.Equal(t, 6.5, sleep)
+
+	require.NoError(t, db.QueryRow(`SELECT mood FROM daily_fields WHERE date = '2024-12-10'`).Scan(&mood))
+	require.False(t, mood.Valid)
+}
@@ -0,0 +1,98 @@
+package timeseries
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatJSONL  = "jsonl"
+	FormatSQLite = "sqlite"
+)
+
+// Table — значения числовых полей по дням. Длительности хранятся в часах
+type Table struct {
+	Fields []string
+	Rows   []Row
+}
+
+type Row struct {
+	Date string
+	// Значения по именам полей; отсутствующее поле означает пропуск
+	Values map[string]float64
+	// true, если за этот день нет ни одной заметки
+	Missing bool
+}
+
+// Build собирает таблицу по датам из FrontMatter.Date. Если за день несколько заметок,
+// значения поля усредняются. При fillMissing дни без заметок добавляются пустыми строками
+func Build(v *vault.Vault, fillMissing bool) *Table {
+	sums := make(map[string]map[string]float64)
+	counts := make(map[string]map[string]int)
+	fields := make(map[string]bool)
+
+	for _, note := range v.Notes {
+		date, ok := note.Date()
+		if !ok {
+			continue
+		}
+		day := date.Format(vault.DateLayout)
+		if sums[day] == nil {
+			sums[day] = make(map[string]float64)
+			counts[day] = make(map[string]int)
+		}
+
+		for key, value := range note.Metadata {
+			if list, ok := value.([]any); ok && len(list) > 0 {
+				value = list[0]
+			}
+			n, ok := vault.NumericValue(value)
+			if !ok {
+				continue
+			}
+			fields[key] = true
+			sums[day][key] += n
+			counts[day][key]++
+		}
+	}
+
+	t := &Table{Fields: make([]string, 0, len(fields))}
+	for field := range fields {
+		t.Fields = append(t.Fields, field)
+	}
+	sort.Strings(t.Fields)
+
+	days := make([]string, 0, len(sums))
+	for day := range sums {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	for i, day := range days {
+		if fillMissing && i > 0 {
+			t.Rows = append(t.Rows, missingDays(days[i-1], day)...)
+		}
+		row := Row{Date: day, Values: make(map[string]float64)}
+		for key, sum := range sums[day] {
+			row.Values[key] = sum / float64(counts[day][key])
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	return t
+}
+
+// missingDays возвращает пустые строки для дней строго между from и to
+func missingDays(from, to string) []Row {
+	start, _ := time.Parse(vault.DateLayout, from)
+	end, _ := time.Parse(vault.DateLayout, to)
+
+	var rows []Row
+	for day := start.AddDate(0, 0, 1); day.Before(end); day = day.AddDate(0, 0, 1) {
+		rows = append(rows, Row{Date: day.Format(vault.DateLayout), Values: map[string]float64{}, Missing: true})
+	}
+	return rows
+}
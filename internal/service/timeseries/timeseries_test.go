@@ -0,0 +1,46 @@
+package timeseries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func testVault() *vault.Vault {
+	note := func(name, date string, meta map[string]any) *vault.Note {
+		return &vault.Note{
+			Name:        name,
+			RelPath:     name + ".md",
+			FrontMatter: &vault.FrontMatter{Date: date},
+			Metadata:    meta,
+		}
+	}
+	return vault.New("", []*vault.Note{
+		note("2024-12-09", "2024-12-09", map[string]any{"mood": 7.0, "sleep": 6*time.Hour + 30*time.Minute, "weather": "rain"}),
+		note("2024-12-09-evening", "2024-12-09", map[string]any{"mood": 5.0}),
+		note("2024-12-12", "2024-12-12", map[string]any{"steps": []any{10000.0, 2000.0}}),
+		note("idea", "", map[string]any{"mood": 1.0}),
+	})
+}
+
+func TestBuild(t *testing.T) {
+	table := Build(testVault(), false)
+
+	require.Equal(t, []string{"mood", "sleep", "steps"}, table.Fields)
+	require.Equal(t, []Row{
+		{Date: "2024-12-09", Values: map[string]float64{"mood": 6, "sleep": 6.5}},
+		{Date: "2024-12-12", Values: map[string]float64{"steps": 10000}},
+	}, table.Rows)
+}
+
+func TestBuild_FillMissing(t *testing.T) {
+	table := Build(testVault(), true)
+
+	require.Len(t, table.Rows, 4)
+	require.Equal(t, Row{Date: "2024-12-10", Values: map[string]float64{}, Missing: true}, table.Rows[1])
+	require.Equal(t, "2024-12-11", table.Rows[2].Date)
+	require.True(t, table.Rows[2].Missing)
+	require.False(t, table.Rows[3].Missing)
+}
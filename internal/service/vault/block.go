@@ -0,0 +1,34 @@
+package vault
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Идентификатор блока Obsidian в конце абзаца или элемента списка, либо отдельной строкой после блока: ^abc-123
+var blockIDRe = regexp.MustCompile(`(?:^|\s)\^([A-Za-z0-9-]+)\s*$`)
+
+type Block struct {
+	ID   string
+	Line int
+}
+
+func parseBlocks(body []byte, firstLine int) []Block {
+	var blocks []Block
+	for i, line := range strings.Split(string(stripCode(body)), "\n") {
+		if m := blockIDRe.FindStringSubmatch(line); m != nil {
+			blocks = append(blocks, Block{ID: m[1], Line: firstLine + i})
+		}
+	}
+	return blocks
+}
+
+// HasBlock сообщает, есть ли в заметке блок с идентификатором id (без ^)
+func (n *Note) HasBlock(id string) bool {
+	for _, b := range n.Blocks {
+		if b.ID == id {
+			return true
+		}
+	}
+	return false
+}
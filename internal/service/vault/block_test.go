@@ -0,0 +1,24 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlocks(t *testing.T) {
+	body := []byte("Абзац со ссылкой ^para-1\n- пункт ^item\n\n| a |\n\n^table\n```\ncode ^not-block\n```\nx^2 и 2 ^ 3\n")
+
+	require.Equal(t, []Block{
+		{ID: "para-1", Line: 3},
+		{ID: "item", Line: 4},
+		{ID: "table", Line: 8},
+	}, parseBlocks(body, 3))
+}
+
+func TestNote_HasBlock(t *testing.T) {
+	note := &Note{Blocks: []Block{{ID: "abc"}}}
+
+	require.True(t, note.HasBlock("abc"))
+	require.False(t, note.HasBlock("def"))
+}
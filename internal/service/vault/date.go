@@ -0,0 +1,120 @@
+package vault
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Источники даты заметки
+const (
+	DateFromFrontMatter = "front_matter"
+	DateFromFilename    = "filename"
+	DateFromModTime     = "mtime"
+)
+
+// DateLayouts — форматы поля date: ISO-дата, русская запись, дата со временем и часовым поясом
+var DateLayouts = []string{
+	DateLayout,
+	"02.01.2006",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"02.01.2006 15:04",
+}
+
+// Дата в имени файла: 2024-12-09.md, Дневник 2024-12-09.md
+var nameDateRe = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+type Option func(*options)
+
+type options struct {
+	location *time.Location
+	fallback []string
+}
+
+func newOptions(opts []Option) options {
+	o := options{location: time.UTC}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLocation задаёт часовой пояс для дат без явного смещения; по умолчанию UTC
+func WithLocation(loc *time.Location) Option {
+	return func(o *options) {
+		if loc != nil {
+			o.location = loc
+		}
+	}
+}
+
+// Location возвращает часовой пояс, заданный опциями; по умолчанию UTC
+func Location(opts ...Option) *time.Location {
+	return newOptions(opts).location
+}
+
+// WithDateFallback задаёт, откуда брать дату заметки без корректного поля date:
+// DateFromFilename и DateFromModTime в порядке перечисления
+func WithDateFallback(sources ...string) Option {
+	return func(o *options) {
+		o.fallback = sources
+	}
+}
+
+// ParseDate разбирает дату в одном из форматов DateLayouts. Даты без смещения считаются датами в loc
+func ParseDate(value string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, layout := range DateLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("не удалось распознать дату %q: ожидается ГГГГ-ММ-ДД, ДД.ММ.ГГГГ или RFC3339", value)
+}
+
+// DateFromName возвращает дату ГГГГ-ММ-ДД из имени файла заметки
+func DateFromName(name string, loc *time.Location) (time.Time, bool) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation(DateLayout, nameDateRe.FindString(name), loc)
+	return t, err == nil
+}
+
+// resolveDate определяет дату заметки по FrontMatter и запасным источникам
+func resolveDate(note *Note, o options) (time.Time, string) {
+	if note.FrontMatter != nil && note.FrontMatter.Date != "" {
+		t, err := ParseDate(note.FrontMatter.Date, o.location)
+		if err == nil {
+			return t, DateFromFrontMatter
+		}
+		log.Warnf("Некорректное поле date в %s: %v", note.RelPath, err)
+	}
+
+	for _, source := range o.fallback {
+		switch source {
+		case DateFromFilename:
+			if t, ok := DateFromName(note.Name, o.location); ok {
+				return t, DateFromFilename
+			}
+		case DateFromModTime:
+			if info, err := os.Stat(note.Path); err == nil {
+				return info.ModTime().In(o.location), DateFromModTime
+			}
+		default:
+			log.Warnf("Неизвестный источник даты заметки: %s", source)
+		}
+	}
+	return time.Time{}, ""
+}
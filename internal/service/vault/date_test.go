@@ -0,0 +1,131 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	testCases := []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "2024-12-09", expected: time.Date(2024, 12, 9, 0, 0, 0, 0, moscow)},
+		{value: "09.12.2024", expected: time.Date(2024, 12, 9, 0, 0, 0, 0, moscow)},
+		{value: "2024-12-09 21:30", expected: time.Date(2024, 12, 9, 21, 30, 0, 0, moscow)},
+		{value: "2024-12-09T21:30:15", expected: time.Date(2024, 12, 9, 21, 30, 15, 0, moscow)},
+		{value: "2024-12-09T21:30:00Z", expected: time.Date(2024, 12, 10, 0, 30, 0, 0, moscow)},
+		{value: "2024-12-09 23:00:00 +0100", expected: time.Date(2024, 12, 10, 1, 0, 0, 0, moscow)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			date, err := ParseDate(tc.value, moscow)
+			require.NoError(t, err)
+			require.True(t, tc.expected.Equal(date), "получено %s", date)
+		})
+	}
+
+	_, err := ParseDate("9 декабря", moscow)
+	require.EqualError(t, err, `не удалось распознать дату "9 декабря": ожидается ГГГГ-ММ-ДД, ДД.ММ.ГГГГ или RFC3339`)
+}
+
+func TestDateFromName(t *testing.T) {
+	date, ok := DateFromName("Дневник 2024-12-09", nil)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 12, 9, 0, 0, 0, 0, time.UTC), date)
+
+	_, ok = DateFromName("Идеи", nil)
+	require.False(t, ok)
+}
+
+func TestParseNote_Date(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	mtime := time.Date(2024, 11, 1, 12, 0, 0, 0, time.UTC)
+	idea := write("Идея.md", "текст")
+	require.NoError(t, os.Chtimes(idea, mtime, mtime))
+
+	testCases := []struct {
+		name     string
+		path     string
+		opts     []Option
+		expected time.Time
+		source   string
+	}{
+		{
+			name:     "FrontMatter",
+			path:     write("a.md", "---\ndate: 09.12.2024\n---\n"),
+			expected: time.Date(2024, 12, 9, 0, 0, 0, 0, time.UTC),
+			source:   DateFromFrontMatter,
+		},
+		{
+			name:     "Имя файла вместо некорректной даты",
+			path:     write("2024-12-10.md", "---\ndate: вчера\n---\n"),
+			opts:     []Option{WithDateFallback(DateFromFilename, DateFromModTime)},
+			expected: time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC),
+			source:   DateFromFilename,
+		},
+		{
+			name:     "Время изменения файла",
+			path:     idea,
+			opts:     []Option{WithDateFallback(DateFromFilename, DateFromModTime)},
+			expected: mtime,
+			source:   DateFromModTime,
+		},
+		{
+			name: "Без запасных источников",
+			path: idea,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			note, err := ParseNote(tc.path, root, tc.opts...)
+			require.NoError(t, err)
+			require.Equal(t, tc.source, note.DateSource)
+			date, ok := note.Date()
+			require.Equal(t, tc.source != "", ok)
+			require.True(t, tc.expected.Equal(date), "получено %s", date)
+		})
+	}
+}
+
+func TestParseNote_DateLocation(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.md")
+	require.NoError(t, os.WriteFile(path, []byte("---\ndate: 2024-12-09 01:30\n---\n"), 0644))
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	note, err := ParseNote(path, root, WithLocation(moscow))
+
+	require.NoError(t, err)
+	date, ok := note.Date()
+	require.True(t, ok)
+	require.Equal(t, "2024-12-09", date.Format(DateLayout))
+	require.Equal(t, "2024-12-08T22:30:00Z", date.UTC().Format(time.RFC3339))
+	require.Equal(t, date, note.Metadata["date"])
+}
+
+func TestParseNote_InlineFieldLocation(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.md")
+	require.NoError(t, os.WriteFile(path, []byte("review:: 2024-12-09\nstart:: 2024-12-09T07:30\n"), 0644))
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	note, err := ParseNote(path, root, WithLocation(moscow))
+
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 12, 9, 0, 0, 0, 0, moscow), note.Metadata["review"])
+	require.Equal(t, time.Date(2024, 12, 9, 7, 30, 0, 0, moscow), note.Metadata["start"])
+}
@@ -0,0 +1,292 @@
+package vault
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// Поле на всю строку: "mood:: 7" или "- sleep:: 6.5h"
+	lineFieldRe = regexp.MustCompile(`^\s*(?:[-*+]\s+)?([\p{L}\p{N}_][\p{L}\p{N}_ /-]*?)::\s*(.*?)\s*$`)
+	// Поле внутри текста: [mood:: 7] или (sleep:: 6.5h)
+	bracketFieldRe = regexp.MustCompile(`[\[(]([\p{L}\p{N}_][\p{L}\p{N}_ /-]*?)::\s*([^\])]*?)\s*[\])]`)
+
+	// Число с точкой; запятая считается десятичным разделителем только в виде 6,5 или 6,25,
+	// чтобы 10,000 не превращалось в 10
+	numberRe       = regexp.MustCompile(`^[-+]?(?:\d+\.?\d*|\.\d+)$`)
+	commaDecimalRe = regexp.MustCompile(`^[-+]?\d+,\d{1,2}$`)
+	durationPartRe = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*([\p{L}]+)`)
+	wikiValueRe    = regexp.MustCompile(`^\[\[([^\]]+)\]\]$`)
+)
+
+var durationUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second, "с": time.Second, "сек": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute, "мин": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour, "ч": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour, "д": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour, "нед": 7 * 24 * time.Hour,
+}
+
+type InlineField struct {
+	Key   string
+	Raw   string
+	Value any
+	Line  int
+	// true для полей вида [key:: value] внутри текста
+	Bracketed bool
+}
+
+// FieldKey нормализует имя поля так же, как Dataview: нижний регистр, пробелы заменены дефисами
+func FieldKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
+
+// ParseValue определяет тип значения поля: число, длительность, дата, ссылка, булево или строка.
+// Даты без смещения разбираются в часовом поясе loc; nil — UTC
+func ParseValue(raw string, loc *time.Location) any {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	if n, ok := parseNumber(raw); ok {
+		return n
+	}
+	switch strings.ToLower(raw) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if m := wikiValueRe.FindStringSubmatch(raw); m != nil {
+		target, alias, _ := strings.Cut(m[1], "|")
+		target, anchor, _ := strings.Cut(target, "#")
+		return Link{Target: strings.TrimSpace(target), Anchor: anchor, Text: alias, Wiki: true}
+	}
+	if d, ok := parseDuration(raw); ok {
+		return d
+	}
+	if t, ok := parseDateValue(raw, loc); ok {
+		return t
+	}
+	return raw
+}
+
+// parseNumber разбирает только десятичную запись: nan, inf, 1e5 и 0x10 остаются строками
+func parseNumber(raw string) (float64, bool) {
+	switch {
+	case numberRe.MatchString(raw):
+	case commaDecimalRe.MatchString(raw):
+		raw = strings.Replace(raw, ",", ".", 1)
+	default:
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, false
+	}
+	return n, true
+}
+
+// FormatValue превращает типизированное значение поля в текст
+func FormatValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	case time.Time:
+		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+			return t.Format(DateLayout)
+		}
+		return t.Format("2006-01-02 15:04")
+	case time.Duration:
+		return formatDuration(t)
+	case Link:
+		return t.Target
+	case []any:
+		parts := make([]string, len(t))
+		for i, item := range t {
+			parts[i] = FormatValue(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(v)
+}
+
+// formatDuration записывает длительность в том же виде, в каком её пишут в заметках: 6h 30m
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0m"
+	}
+	var parts []string
+	for _, unit := range []struct {
+		size time.Duration
+		name string
+	}{{24 * time.Hour, "d"}, {time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"}} {
+		if n := d / unit.size; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", n, unit.name))
+			d -= n * unit.size
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// NumericValue возвращает числовое представление значения; длительности переводятся в часы
+func NumericValue(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		// .nan и .inf из YAML не годятся для отчётов и экспорта
+		return t, !math.IsNaN(t) && !math.IsInf(t, 0)
+	case int:
+		return float64(t), true
+	case time.Duration:
+		return t.Hours(), true
+	}
+	return 0, false
+}
+
+func parseDuration(raw string) (time.Duration, bool) {
+	matches := durationPartRe.FindAllStringSubmatchIndex(raw, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+
+	var total time.Duration
+	covered := 0
+	for _, m := range matches {
+		// Между частями допускаются только пробелы: "1h 30m"
+		if strings.TrimSpace(raw[covered:m[0]]) != "" {
+			return 0, false
+		}
+		unit, ok := durationUnits[strings.ToLower(raw[m[4]:m[5]])]
+		if !ok {
+			return 0, false
+		}
+		n, err := strconv.ParseFloat(strings.Replace(raw[m[2]:m[3]], ",", ".", 1), 64)
+		if err != nil {
+			return 0, false
+		}
+		total += time.Duration(n * float64(unit))
+		covered = m[1]
+	}
+	if strings.TrimSpace(raw[covered:]) != "" {
+		return 0, false
+	}
+	return total, true
+}
+
+func parseDateValue(raw string, loc *time.Location) (time.Time, bool) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, layout := range []string{DateLayout, "2006-01-02T15:04", "2006-01-02T15:04:05", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseInlineFields находит поля key:: value вне блоков кода.
+// Поля внутри задач относятся к задаче (срок, приоритет), а не к заметке, и пропускаются
+func parseInlineFields(body []byte, firstLine int, loc *time.Location) []InlineField {
+	var fields []InlineField
+	for i, line := range strings.Split(string(stripCode(body)), "\n") {
+		if _, isTask := ParseTask(line); isTask {
+			continue
+		}
+		lineNo := firstLine + i
+
+		if m := lineFieldRe.FindStringSubmatch(line); m != nil {
+			fields = append(fields, InlineField{Key: FieldKey(m[1]), Raw: m[2], Value: ParseValue(m[2], loc), Line: lineNo})
+			continue
+		}
+		for _, m := range bracketFieldRe.FindAllStringSubmatch(line, -1) {
+			fields = append(fields, InlineField{Key: FieldKey(m[1]), Raw: m[2], Value: ParseValue(m[2], loc), Line: lineNo, Bracketed: true})
+		}
+	}
+	return fields
+}
+
+// buildMetadata объединяет FrontMatter и поля из текста в одну карту с типизированными значениями.
+// Если поле встречается несколько раз, значения собираются в список
+func buildMetadata(fm *FrontMatter, fields []InlineField, loc *time.Location) map[string]any {
+	meta := make(map[string]any)
+	add := func(key string, value any) {
+		if value == nil {
+			return
+		}
+		existing, ok := meta[key]
+		if !ok {
+			meta[key] = value
+			return
+		}
+		if list, ok := existing.([]any); ok {
+			meta[key] = append(list, value)
+			return
+		}
+		meta[key] = []any{existing, value}
+	}
+
+	if fm != nil {
+		if fm.Date != "" {
+			add("date", ParseValue(fm.Date, loc))
+		}
+		if fm.Author != "" {
+			add("author", fm.Author)
+		}
+		if len(fm.Tags) > 0 {
+			tags := make([]any, len(fm.Tags))
+			for i, tag := range fm.Tags {
+				tags[i] = tag
+			}
+			meta["tags"] = tags
+		}
+		if len(fm.Aliases) > 0 {
+			aliases := make([]any, len(fm.Aliases))
+			for i, alias := range fm.Aliases {
+				aliases[i] = alias
+			}
+			meta["aliases"] = aliases
+		}
+		meta["closed"] = fm.Closed
+		for key, value := range fm.Extra {
+			add(FieldKey(key), normalizeYAMLValue(value, loc))
+		}
+	}
+
+	for _, f := range fields {
+		add(f.Key, f.Value)
+	}
+	return meta
+}
+
+// normalizeYAMLValue приводит значения из YAML к тем же типам, что и поля из текста
+func normalizeYAMLValue(v any, loc *time.Location) any {
+	switch t := v.(type) {
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	case string:
+		return ParseValue(t, loc)
+	case []any:
+		list := make([]any, len(t))
+		for i, item := range t {
+			list[i] = normalizeYAMLValue(item, loc)
+		}
+		return list
+	}
+	return v
+}
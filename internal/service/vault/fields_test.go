@@ -0,0 +1,119 @@
+package vault
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValue(t *testing.T) {
+	testCases := []struct {
+		raw      string
+		expected any
+	}{
+		{raw: "7", expected: 7.0},
+		{raw: "6,5", expected: 6.5},
+		{raw: "6.5h", expected: 6*time.Hour + 30*time.Minute},
+		{raw: "1h 30m", expected: 90 * time.Minute},
+		{raw: "45 мин", expected: 45 * time.Minute},
+		{raw: "2024-12-09", expected: time.Date(2024, 12, 9, 0, 0, 0, 0, time.UTC)},
+		{raw: "2024-12-09T07:30", expected: time.Date(2024, 12, 9, 7, 30, 0, 0, time.UTC)},
+		{raw: "[[Проект Альфа|Альфа]]", expected: Link{Target: "Проект Альфа", Text: "Альфа", Wiki: true}},
+		{raw: "true", expected: true},
+		{raw: "хорошо", expected: "хорошо"},
+		{raw: "3 кота", expected: "3 кота"},
+		{raw: "1e5", expected: "1e5"},
+		{raw: "-0.25", expected: -0.25},
+		{raw: "6,25", expected: 6.25},
+		{raw: "10,000", expected: "10,000"},
+		{raw: "1,2,3", expected: "1,2,3"},
+		{raw: "nan", expected: "nan"},
+		{raw: "Inf", expected: "Inf"},
+		{raw: "0x10", expected: "0x10"},
+		{raw: "  ", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.raw, func(t *testing.T) {
+			require.Equal(t, tc.expected, ParseValue(tc.raw, nil))
+		})
+	}
+}
+
+func TestParseInlineFields(t *testing.T) {
+	body := []byte(`mood:: 7
+- Sleep Quality:: 6.5h
+Сегодня [energy:: high] и (weight:: 70.5).
+- [ ] задача [due:: 2024-12-10]
+` + "```\ncode:: 1\n```\n`inline:: 2`\n")
+
+	fields := parseInlineFields(body, 10, nil)
+
+	require.Equal(t, []InlineField{
+		{Key: "mood", Raw: "7", Value: 7.0, Line: 10},
+		{Key: "sleep-quality", Raw: "6.5h", Value: 6*time.Hour + 30*time.Minute, Line: 11},
+		{Key: "energy", Raw: "high", Value: "high", Line: 12, Bracketed: true},
+		{Key: "weight", Raw: "70.5", Value: 70.5, Line: 12, Bracketed: true},
+	}, fields)
+}
+
+func TestBuildMetadata(t *testing.T) {
+	fm := &FrontMatter{
+		Date:   "2024-12-09",
+		Author: "ANkulagin",
+		Tags:   []string{"#daily"},
+		Extra:  map[string]any{"Mood": 5, "steps": "10000", "projects": []any{"[[Alpha]]"}},
+	}
+	fields := []InlineField{
+		{Key: "mood", Value: 7.0},
+		{Key: "sleep", Value: 8 * time.Hour},
+	}
+
+	meta := buildMetadata(fm, fields, nil)
+
+	require.Equal(t, time.Date(2024, 12, 9, 0, 0, 0, 0, time.UTC), meta["date"])
+	require.Equal(t, "ANkulagin", meta["author"])
+	require.Equal(t, []any{"#daily"}, meta["tags"])
+	require.Equal(t, false, meta["closed"])
+	require.Equal(t, []any{5.0, 7.0}, meta["mood"])
+	require.Equal(t, 10000.0, meta["steps"])
+	require.Equal(t, 8*time.Hour, meta["sleep"])
+	require.Equal(t, []any{Link{Target: "Alpha", Wiki: true}}, meta["projects"])
+}
+
+func TestFormatValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "Число", value: 6.5, expected: "6.5"},
+		{name: "Длительность", value: 6*time.Hour + 30*time.Minute, expected: "6h 30m"},
+		{name: "Дата", value: time.Date(2024, 12, 9, 0, 0, 0, 0, time.UTC), expected: "2024-12-09"},
+		{name: "Дата со временем", value: time.Date(2024, 12, 9, 7, 5, 0, 0, time.UTC), expected: "2024-12-09 07:05"},
+		{name: "Список", value: []any{1.0, "два", Link{Target: "Три"}}, expected: "1, два, Три"},
+		{name: "Пусто", value: nil, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, FormatValue(tc.value))
+		})
+	}
+}
+
+func TestNumericValue(t *testing.T) {
+	n, ok := NumericValue(90 * time.Minute)
+	require.True(t, ok)
+	require.Equal(t, 1.5, n)
+
+	_, ok = NumericValue("7")
+	require.False(t, ok)
+
+	_, ok = NumericValue(math.NaN())
+	require.False(t, ok)
+	_, ok = NumericValue(math.Inf(1))
+	require.False(t, ok)
+}
@@ -0,0 +1,82 @@
+package vault
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+type FrontMatter struct {
+	Date   string   `yaml:"date"`
+	Author string   `yaml:"author"`
+	Tags   []string `yaml:"tags"`
+	Closed bool     `yaml:"closed"`
+	// Прежние и альтернативные названия заметки; вики-ссылки по ним ведут на заметку
+	Aliases StringList `yaml:"aliases"`
+	// Остальные поля FrontMatter (mood, sleep и т.п.)
+	Extra map[string]any `yaml:",inline"`
+}
+
+func SplitFrontMatter(content []byte) (*FrontMatter, []byte, error) {
+	_, raw, body, ok := SplitRaw(content)
+	if !ok {
+		return &FrontMatter{}, content, nil
+	}
+
+	var fm FrontMatter
+	if err := yaml.Unmarshal(raw, &fm); err != nil {
+		return nil, nil, fmt.Errorf("не удалось распарсить FrontMatter: %v", err)
+	}
+
+	return &fm, body, nil
+}
+
+// FrontMatterNode возвращает FrontMatter как дерево yaml.Node без приведения к типам FrontMatter,
+// чтобы проверять значения с номерами строк. Номер строки узла в файле — offset + node.Line.
+// Для заметки без FrontMatter возвращает nil
+func FrontMatterNode(content []byte) (*yaml.Node, int, error) {
+	head, raw, _, ok := SplitRaw(content)
+	if !ok {
+		return nil, 0, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, 0, fmt.Errorf("не удалось распарсить FrontMatter: %v", err)
+	}
+	// YAML начинается на строке первого разделителя ---
+	offset := bytes.Count(head, []byte("\n"))
+	if len(doc.Content) == 0 {
+		return nil, offset, nil
+	}
+	return doc.Content[0], offset, nil
+}
+
+// SplitRaw делит заметку на текст до первого разделителя ---, YAML между разделителями и тело.
+// ok=false, если FrontMatter нет
+func SplitRaw(content []byte) (head, raw, body []byte, ok bool) {
+	parts := bytes.SplitN(content, []byte("---"), 3)
+	if len(parts) < 3 {
+		return nil, nil, content, false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// StringList принимает в YAML и список, и одну строку: aliases: Старое имя
+type StringList []string
+
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		if value.Value != "" {
+			*l = StringList{value.Value}
+		}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
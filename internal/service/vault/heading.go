@@ -0,0 +1,69 @@
+package vault
+
+import (
+	"regexp"
+	"strings"
+)
+
+var headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+
+type Heading struct {
+	Level int
+	Text  string
+	// id якоря заголовка на странице, уникальный в пределах заметки
+	ID   string
+	Line int
+}
+
+func parseHeadings(body []byte, firstLine int) []Heading {
+	var headings []Heading
+	slugger := NewSlugger()
+	inFence := false
+	for i, line := range strings.Split(string(body), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			headings = append(headings, Heading{
+				Level: len(m[1]),
+				Text:  m[2],
+				ID:    slugger.Unique(headingText(m[2])),
+				Line:  firstLine + i,
+			})
+		}
+	}
+	return headings
+}
+
+// Title возвращает заголовок заметки: поле title, первый заголовок первого уровня или имя файла
+func (n *Note) Title() string {
+	if title, ok := n.Metadata["title"].(string); ok && title != "" {
+		return title
+	}
+	for _, h := range n.Headings {
+		if h.Level == 1 {
+			return h.Text
+		}
+	}
+	return n.Name
+}
+
+// HasHeading сообщает, есть ли в заметке заголовок с текстом или id anchor.
+// Для вложенных ссылок Obsidian «Раздел#Подраздел» проверяется последний заголовок
+func (n *Note) HasHeading(anchor string) bool {
+	if i := strings.LastIndex(anchor, "#"); i >= 0 {
+		anchor = anchor[i+1:]
+	}
+	slug := Slug(headingText(anchor))
+	for _, h := range n.Headings {
+		if h.ID == slug || Slug(headingText(h.Text)) == slug {
+			return true
+		}
+	}
+	return false
+}
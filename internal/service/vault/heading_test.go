@@ -0,0 +1,89 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeadings(t *testing.T) {
+	body := []byte("# День\n\ntext\n## Планы ##\n```\n# не заголовок\n```\n#тег\n### Итоги")
+
+	require.Equal(t, []Heading{
+		{Level: 1, Text: "День", ID: "день", Line: 5},
+		{Level: 2, Text: "Планы", ID: "планы", Line: 8},
+		{Level: 3, Text: "Итоги", ID: "итоги", Line: 13},
+	}, parseHeadings(body, 5))
+}
+
+func TestParseHeadings_IDs(t *testing.T) {
+	body := []byte("# Итоги\n## Итоги\n## **Жирный** [[Заметка|ссылка]]\n## [Текст](a.md) <b>html</b>\n## ???")
+
+	var ids []string
+	for _, h := range parseHeadings(body, 1) {
+		ids = append(ids, h.ID)
+	}
+	require.Equal(t, []string{"итоги", "итоги-1", "жирный-ссылка", "текст-html", "section"}, ids)
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Ёжик в тумане":        "ёжик-в-тумане",
+		"Hello, World!":        "hello-world",
+		"  Планы -- на  2024 ": "планы-на-2024",
+		"C++ и Go":             "c-и-go",
+		"snake_case":           "snakecase",
+		"":                     "section",
+	}
+	for text, want := range tests {
+		require.Equal(t, want, Slug(text), text)
+	}
+}
+
+func TestSlugger_Unique(t *testing.T) {
+	s := NewSlugger()
+
+	require.Equal(t, "итоги", s.Unique("Итоги"))
+	require.Equal(t, "итоги-1", s.Unique("итоги"))
+	require.Equal(t, "итоги-1-1", s.Unique("Итоги 1"))
+	require.Equal(t, "итоги-2", s.Unique("Итоги!"))
+}
+
+func TestNote_Title(t *testing.T) {
+	tests := []struct {
+		name string
+		note *Note
+		want string
+	}{
+		{
+			name: "Поле title",
+			note: &Note{Name: "a", Metadata: map[string]any{"title": "Заголовок"}, Headings: []Heading{{Level: 1, Text: "H1"}}},
+			want: "Заголовок",
+		},
+		{
+			name: "Первый заголовок первого уровня",
+			note: &Note{Name: "a", Headings: []Heading{{Level: 2, Text: "H2"}, {Level: 1, Text: "H1"}}},
+			want: "H1",
+		},
+		{
+			name: "Имя файла",
+			note: &Note{Name: "a"},
+			want: "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.note.Title())
+		})
+	}
+}
+
+func TestNote_HasHeading(t *testing.T) {
+	note := &Note{Headings: parseHeadings([]byte("# День\n## Итоги дня\n## Итоги дня"), 1)}
+
+	require.True(t, note.HasHeading("Итоги дня"))
+	require.True(t, note.HasHeading("итоги-дня-1"))
+	require.True(t, note.HasHeading("День#Итоги дня"))
+	require.False(t, note.HasHeading("Планы"))
+}
@@ -0,0 +1,74 @@
+package vault
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// [[Note]], [[Note#Heading]], [[Note#^block|Текст]], ![[image.png]]
+	wikiLinkRe = regexp.MustCompile(`(!?)\[\[([^\[\]|#]*)(#[^\[\]|]*)?(?:\|([^\[\]]*))?\]\]`)
+	// [Текст](../path/note.md#heading), ![alt](image.png)
+	mdLinkRe = regexp.MustCompile(`(!?)\[([^\[\]]*)\]\(<?([^()\s<>]+)>?(?:\s+"[^"]*")?\)`)
+)
+
+type Link struct {
+	// Имя заметки для вики-ссылок или путь для обычных Markdown-ссылок
+	Target string
+	// Заголовок или идентификатор блока (^id) без символа #
+	Anchor string
+	Text   string
+	Line   int
+	Wiki   bool
+	Embed  bool
+}
+
+// IsExternal сообщает, ведёт ли ссылка за пределы хранилища (http, mailto и т.п.)
+func (l Link) IsExternal() bool {
+	if l.Wiki {
+		return false
+	}
+	if strings.HasPrefix(l.Target, "//") {
+		return true
+	}
+	u, err := url.Parse(l.Target)
+	return err == nil && u.Scheme != ""
+}
+
+func parseLinks(body []byte, firstLine int) []Link {
+	var links []Link
+
+	for i, line := range strings.Split(string(stripCode(body)), "\n") {
+		lineNo := firstLine + i
+
+		for _, m := range wikiLinkRe.FindAllStringSubmatch(line, -1) {
+			links = append(links, Link{
+				Target: strings.TrimSpace(m[2]),
+				Anchor: strings.TrimPrefix(strings.TrimSpace(m[3]), "#"),
+				Text:   strings.TrimSpace(m[4]),
+				Line:   lineNo,
+				Wiki:   true,
+				Embed:  m[1] == "!",
+			})
+		}
+
+		// Вики-ссылки убираем, чтобы их содержимое не разбиралось как Markdown-ссылка
+		line = wikiLinkRe.ReplaceAllString(line, "")
+		for _, m := range mdLinkRe.FindAllStringSubmatch(line, -1) {
+			target, anchor, _ := strings.Cut(m[3], "#")
+			if unescaped, err := url.PathUnescape(target); err == nil {
+				target = unescaped
+			}
+			links = append(links, Link{
+				Target: target,
+				Anchor: anchor,
+				Text:   m[2],
+				Line:   lineNo,
+				Embed:  m[1] == "!",
+			})
+		}
+	}
+
+	return links
+}
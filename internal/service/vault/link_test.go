@@ -0,0 +1,44 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLinks(t *testing.T) {
+	body := []byte(`# Заголовок
+См. [[Проект Альфа]] и [[2024-12-08#^abc123|вчера]].
+![[diagram.png]]
+[Отчёт](../reports/Итоги%20года.md#Январь) и [сайт](https://example.com)
+` + "```\n[[в коде]]\n```\n" + "`[[тоже код]]`\n")
+
+	links := parseLinks(body, 5)
+
+	require.Equal(t, []Link{
+		{Target: "Проект Альфа", Line: 6, Wiki: true},
+		{Target: "2024-12-08", Anchor: "^abc123", Text: "вчера", Line: 6, Wiki: true},
+		{Target: "diagram.png", Line: 7, Wiki: true, Embed: true},
+		{Target: "../reports/Итоги года.md", Anchor: "Январь", Text: "Отчёт", Line: 8},
+		{Target: "https://example.com", Text: "сайт", Line: 8},
+	}, links)
+}
+
+func TestLink_IsExternal(t *testing.T) {
+	testCases := []struct {
+		link     Link
+		expected bool
+	}{
+		{link: Link{Target: "https://example.com"}, expected: true},
+		{link: Link{Target: "mailto:me@example.com"}, expected: true},
+		{link: Link{Target: "//cdn.example.com/x.js"}, expected: true},
+		{link: Link{Target: "../notes/a.md"}, expected: false},
+		{link: Link{Target: "https://example.com", Wiki: true}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.link.Target, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.link.IsExternal())
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package vault
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const DateLayout = "2006-01-02"
+
+// Тег в тексте заметки: #tag, #project/alpha, #заметки
+var inlineTagRe = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]+)`)
+
+type Note struct {
+	Path        string
+	RelPath     string
+	Name        string
+	FrontMatter *FrontMatter
+	Body        []byte
+	Tags        []string
+	Links       []Link
+	Tasks       []Task
+	Headings    []Heading
+	Blocks      []Block
+	Fields      []InlineField
+	// FrontMatter и поля key:: value из текста с типизированными значениями
+	Metadata  map[string]any
+	WordCount int
+	// Номер строки файла, с которой начинается тело заметки (после FrontMatter)
+	BodyLine int
+	// Дата заметки и её источник (DateFromFrontMatter, DateFromFilename, DateFromModTime); пусто — даты нет
+	Time       time.Time
+	DateSource string
+}
+
+func ParseNote(path, root string, opts ...Option) (*Note, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл: %v", err)
+	}
+
+	fm, body, err := SplitFrontMatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при разборе FrontMatter: %v", err)
+	}
+
+	relPath, err := filepath.Rel(root, path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось определить относительный путь: %v", err)
+	}
+
+	bodyLine := 1 + bytes.Count(content[:len(content)-len(body)], []byte("\n"))
+	o := newOptions(opts)
+	fields := parseInlineFields(body, bodyLine, o.location)
+
+	note := &Note{
+		Path:        path,
+		RelPath:     relPath,
+		Name:        strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		FrontMatter: fm,
+		Body:        body,
+		Tags:        collectTags(fm.Tags, body),
+		Links:       parseLinks(body, bodyLine),
+		Tasks:       parseTasks(body, bodyLine),
+		Headings:    parseHeadings(body, bodyLine),
+		Blocks:      parseBlocks(body, bodyLine),
+		Fields:      fields,
+		Metadata:    buildMetadata(fm, fields, o.location),
+		WordCount:   len(strings.Fields(string(body))),
+		BodyLine:    bodyLine,
+	}
+
+	note.Time, note.DateSource = resolveDate(note, o)
+	// Дата с учётом часового пояса; список из FrontMatter и поля date:: в тексте не трогаем
+	if _, isList := note.Metadata["date"].([]any); note.DateSource == DateFromFrontMatter && !isList {
+		note.Metadata["date"] = note.Time
+	}
+	return note, nil
+}
+
+// Date возвращает дату заметки, определённую при разборе, или дату из FrontMatter
+// для заметок, созданных без ParseNote
+func (n *Note) Date() (time.Time, bool) {
+	if n.DateSource != "" {
+		return n.Time, true
+	}
+	if n.FrontMatter == nil || n.FrontMatter.Date == "" {
+		return time.Time{}, false
+	}
+	t, err := ParseDate(n.FrontMatter.Date, time.UTC)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// collectTags объединяет теги из FrontMatter и из текста заметки,
+// приводя их к виду "#tag" и убирая дубликаты
+func collectTags(fmTags []string, body []byte) []string {
+	seen := make(map[string]bool)
+	var tags []string
+
+	add := func(tag string) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return
+		}
+		if !strings.HasPrefix(tag, "#") {
+			tag = "#" + tag
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	for _, tag := range fmTags {
+		add(tag)
+	}
+	for _, m := range inlineTagRe.FindAllSubmatch(stripCode(body), -1) {
+		add(string(m[1]))
+	}
+
+	return tags
+}
+
+// stripCode вырезает блоки и спаны кода, чтобы их содержимое не попадало в теги и ссылки.
+// Строки блоков кода заменяются пустыми, чтобы номера строк не сдвигались
+func stripCode(body []byte) []byte {
+	lines := strings.Split(string(body), "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			lines[i] = ""
+			continue
+		}
+		if inFence {
+			lines[i] = ""
+			continue
+		}
+		lines[i] = inlineCodeRe.ReplaceAllString(line, "")
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+var inlineCodeRe = regexp.MustCompile("`[^`]*`")
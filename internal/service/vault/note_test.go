@@ -0,0 +1,137 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseNote_Success(t *testing.T) {
+	root, err := os.MkdirTemp("", "vault")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	content := `---
+date: 2024-12-09
+author: ANkulagin
+tags:
+  - "#daily"
+  - notes
+closed: true
+---
+# Заголовок
+
+Сегодня работал над #project/alpha и #daily.
+` + "```go\n// #not-a-tag\n```\n"
+
+	path := filepath.Join(root, "2024-12-09.md")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	note, err := ParseNote(path, root)
+	require.NoError(t, err)
+
+	require.Equal(t, "2024-12-09.md", note.RelPath)
+	require.Equal(t, "2024-12-09", note.Name)
+	require.Equal(t, "ANkulagin", note.FrontMatter.Author)
+	require.True(t, note.FrontMatter.Closed)
+	require.Equal(t, []string{"#daily", "#notes", "#project/alpha"}, note.Tags)
+
+	date, ok := note.Date()
+	require.True(t, ok)
+	require.Equal(t, "2024-12-09", date.Format(DateLayout))
+}
+
+func TestParseNote_Error(t *testing.T) {
+	testCases := []struct {
+		name           string
+		content        string
+		expectedErrMsg string
+	}{
+		{
+			name: "Invalid FrontMatter format",
+			content: `---
+tags:
+  - "#notes
+---
+`,
+			expectedErrMsg: "ошибка при разборе FrontMatter: не удалось распарсить FrontMatter:",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := os.MkdirTemp("", "vault")
+			require.NoError(t, err)
+			defer os.RemoveAll(root)
+
+			path := filepath.Join(root, "bad.md")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+
+			_, err = ParseNote(path, root)
+
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedErrMsg)
+		})
+	}
+}
+
+func TestNoteDate_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		fm   *FrontMatter
+	}{
+		{name: "Без FrontMatter", fm: nil},
+		{name: "Пустая дата", fm: &FrontMatter{}},
+		{name: "Некорректная дата", fm: &FrontMatter{Date: "вчера"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			note := &Note{FrontMatter: tc.fm}
+
+			_, ok := note.Date()
+
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestSplitFrontMatter_Aliases(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected StringList
+	}{
+		{name: "Список", content: "---\naliases: [Старое имя, old]\n---\n", expected: StringList{"Старое имя", "old"}},
+		{name: "Одна строка", content: "---\naliases: Старое имя\n---\n", expected: StringList{"Старое имя"}},
+		{name: "Пусто", content: "---\naliases:\n---\n", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fm, _, err := SplitFrontMatter([]byte(tc.content))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, fm.Aliases)
+			require.NotContains(t, fm.Extra, "aliases")
+		})
+	}
+}
+
+func TestFrontMatterNode(t *testing.T) {
+	node, offset, err := FrontMatterNode([]byte("\n---\ndate: 2024-12-09\ntags: [a]\n---\n# Текст"))
+
+	require.NoError(t, err)
+	require.Equal(t, yaml.MappingNode, node.Kind)
+	require.Equal(t, "tags", node.Content[2].Value)
+	require.Equal(t, 4, offset+node.Content[2].Line)
+
+	node, _, err = FrontMatterNode([]byte("# Без FrontMatter"))
+	require.NoError(t, err)
+	require.Nil(t, node)
+
+	_, _, err = FrontMatterNode([]byte("---\ndate: [\n---\n"))
+	require.Error(t, err)
+}
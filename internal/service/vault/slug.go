@@ -0,0 +1,72 @@
+package vault
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Слаг для заголовка без букв и цифр
+const emptySlug = "section"
+
+var htmlTagRe = regexp.MustCompile(`<[^>]+>`)
+
+// Slug превращает текст заголовка в id: буквы любого алфавита и цифры в нижнем регистре,
+// пробелы и дефисы — в один дефис, остальная пунктуация отбрасывается. «Ёжик в тумане» → «ёжик-в-тумане»
+func Slug(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-':
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		return emptySlug
+	}
+	return b.String()
+}
+
+// Slugger выдаёт уникальные слаги в пределах страницы: повторы получают суффиксы -1, -2 и т.д.
+type Slugger struct {
+	seen map[string]bool
+}
+
+func NewSlugger() *Slugger {
+	return &Slugger{seen: make(map[string]bool)}
+}
+
+func (s *Slugger) Unique(text string) string {
+	slug := Slug(text)
+	id := slug
+	for i := 1; s.seen[id]; i++ {
+		id = fmt.Sprintf("%s-%d", slug, i)
+	}
+	s.seen[id] = true
+	return id
+}
+
+// headingText возвращает видимый текст заголовка: ссылки заменяются их текстом, HTML-теги убираются
+func headingText(raw string) string {
+	raw = wikiLinkRe.ReplaceAllStringFunc(raw, func(match string) string {
+		m := wikiLinkRe.FindStringSubmatch(match)
+		if text := strings.TrimSpace(m[4]); text != "" {
+			return text
+		}
+		target, anchor := strings.TrimSpace(m[2]), strings.TrimPrefix(m[3], "#")
+		if anchor == "" {
+			return target
+		}
+		return strings.TrimPrefix(target+" > "+anchor, " > ")
+	})
+	raw = mdLinkRe.ReplaceAllString(raw, "$2")
+	return htmlTagRe.ReplaceAllString(raw, "")
+}
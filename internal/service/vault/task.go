@@ -0,0 +1,123 @@
+package vault
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	TaskOpen       = "open"
+	TaskDone       = "done"
+	TaskCancelled  = "cancelled"
+	TaskInProgress = "in_progress"
+	// Задача перенесена в другую заметку
+	TaskMigrated = "migrated"
+)
+
+var (
+	// - [ ] задача, * [x] задача, 1. [/] задача, - [>] перенесённая задача
+	taskRe = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+\[([ xX/>-])\]\s+(.*)$`)
+
+	// Срок в формате плагина Tasks (📅 2024-12-10) или Dataview (due:: 2024-12-10)
+	dueEmojiRe = regexp.MustCompile(`📅\s*(\d{4}-\d{2}-\d{2})`)
+	dueFieldRe = regexp.MustCompile(`\[?due::\s*(\d{4}-\d{2}-\d{2})\]?`)
+	doneDateRe = regexp.MustCompile(`✅\s*(\d{4}-\d{2}-\d{2})`)
+
+	priorityFieldRe = regexp.MustCompile(`\[?priority::\s*(\w+)\]?`)
+	priorityEmoji   = []struct {
+		emoji    string
+		priority string
+	}{
+		{"🔺", "highest"},
+		{"⏫", "high"},
+		{"🔼", "medium"},
+		{"🔽", "low"},
+		{"⏬", "lowest"},
+	}
+)
+
+type Task struct {
+	Text      string `json:"text"`
+	Status    string `json:"status"`
+	Due       string `json:"due,omitempty"`
+	Completed string `json:"completed,omitempty"`
+	Priority  string `json:"priority,omitempty"`
+	Line      int    `json:"line"`
+	// Уровень вложенности в списке (0 — верхний уровень)
+	Indent int `json:"indent"`
+}
+
+func (t Task) IsOpen() bool {
+	return t.Status == TaskOpen || t.Status == TaskInProgress
+}
+
+// ParseTask разбирает строку списка задач. Второе значение false, если строка не является задачей
+func ParseTask(line string) (Task, bool) {
+	m := taskRe.FindStringSubmatch(line)
+	if m == nil {
+		return Task{}, false
+	}
+
+	task := Task{
+		Indent: len(strings.ReplaceAll(m[1], "\t", "  ")) / 2,
+	}
+
+	switch m[2] {
+	case "x", "X":
+		task.Status = TaskDone
+	case "-":
+		task.Status = TaskCancelled
+	case "/":
+		task.Status = TaskInProgress
+	case ">":
+		task.Status = TaskMigrated
+	default:
+		task.Status = TaskOpen
+	}
+
+	text := m[3]
+	if d := dueEmojiRe.FindStringSubmatch(text); d != nil {
+		task.Due = d[1]
+	} else if d := dueFieldRe.FindStringSubmatch(text); d != nil {
+		task.Due = d[1]
+	}
+	if d := doneDateRe.FindStringSubmatch(text); d != nil {
+		task.Completed = d[1]
+	}
+	if p := priorityFieldRe.FindStringSubmatch(text); p != nil {
+		task.Priority = strings.ToLower(p[1])
+	}
+	for _, p := range priorityEmoji {
+		if strings.Contains(text, p.emoji) {
+			task.Priority = p.priority
+			text = strings.ReplaceAll(text, p.emoji, "")
+		}
+	}
+
+	for _, re := range []*regexp.Regexp{dueEmojiRe, dueFieldRe, doneDateRe, priorityFieldRe} {
+		text = re.ReplaceAllString(text, "")
+	}
+	task.Text = strings.Join(strings.Fields(text), " ")
+
+	return task, true
+}
+
+func parseTasks(body []byte, firstLine int) []Task {
+	var tasks []Task
+	inFence := false
+	for i, line := range strings.Split(string(body), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if task, ok := ParseTask(line); ok {
+			task.Line = firstLine + i
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,76 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTask(t *testing.T) {
+	testCases := []struct {
+		name     string
+		line     string
+		expected Task
+	}{
+		{
+			name:     "Открытая задача",
+			line:     "- [ ] Купить молоко",
+			expected: Task{Text: "Купить молоко", Status: TaskOpen},
+		},
+		{
+			name:     "Выполненная задача с датой завершения",
+			line:     "* [x] Отправить отчёт ✅ 2024-12-09",
+			expected: Task{Text: "Отправить отчёт", Status: TaskDone, Completed: "2024-12-09"},
+		},
+		{
+			name:     "Срок и приоритет в формате Tasks",
+			line:     "  - [ ] Подготовить релиз ⏫ 📅 2024-12-10",
+			expected: Task{Text: "Подготовить релиз", Status: TaskOpen, Due: "2024-12-10", Priority: "high", Indent: 1},
+		},
+		{
+			name:     "Срок и приоритет в формате Dataview",
+			line:     "1. [/] Ревью [due:: 2024-12-11] [priority:: Low]",
+			expected: Task{Text: "Ревью", Status: TaskInProgress, Due: "2024-12-11", Priority: "low"},
+		},
+		{
+			name:     "Отменённая задача",
+			line:     "- [-] Старая идея",
+			expected: Task{Text: "Старая идея", Status: TaskCancelled},
+		},
+		{
+			name:     "Перенесённая задача",
+			line:     "- [>] Позвонить",
+			expected: Task{Text: "Позвонить", Status: TaskMigrated},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task, ok := ParseTask(tc.line)
+
+			require.True(t, ok)
+			require.Equal(t, tc.expected, task)
+		})
+	}
+}
+
+func TestParseTask_NotATask(t *testing.T) {
+	for _, line := range []string{"- обычный пункт", "[ ] без маркера списка", "- [ ]", "# Заголовок"} {
+		t.Run(line, func(t *testing.T) {
+			_, ok := ParseTask(line)
+
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestParseTasks_SkipsCodeBlocks(t *testing.T) {
+	body := []byte("- [ ] первая\n```\n- [ ] в коде\n```\n- [x] вторая\n")
+
+	tasks := parseTasks(body, 3)
+
+	require.Len(t, tasks, 2)
+	require.Equal(t, 3, tasks[0].Line)
+	require.Equal(t, 7, tasks[1].Line)
+	require.False(t, tasks[1].IsOpen())
+}
@@ -0,0 +1,147 @@
+package vault
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Vault struct {
+	Root  string
+	Notes []*Note
+
+	byName  map[string][]*Note
+	byPath  map[string]*Note
+	byAlias map[string]*Note
+}
+
+func New(root string, notes []*Note) *Vault {
+	v := &Vault{
+		Root:    root,
+		Notes:   notes,
+		byName:  make(map[string][]*Note),
+		byPath:  make(map[string]*Note),
+		byAlias: make(map[string]*Note),
+	}
+
+	// Порядок обхода не должен влиять на отчёты
+	sort.Slice(v.Notes, func(i, j int) bool {
+		return v.Notes[i].RelPath < v.Notes[j].RelPath
+	})
+
+	for _, note := range v.Notes {
+		if note.Metadata == nil {
+			note.Metadata = buildMetadata(note.FrontMatter, note.Fields, nil)
+		}
+		key := strings.ToLower(note.Name)
+		v.byName[key] = append(v.byName[key], note)
+		v.byPath[filepath.ToSlash(note.RelPath)] = note
+	}
+	for _, note := range v.Notes {
+		if note.FrontMatter == nil {
+			continue
+		}
+		for _, alias := range note.FrontMatter.Aliases {
+			key := strings.ToLower(strings.TrimSpace(alias))
+			if other, ok := v.byAlias[key]; ok && other != note {
+				log.Warnf("Псевдоним %s есть у заметок %s и %s, используется первая", alias, other.RelPath, note.RelPath)
+				continue
+			}
+			v.byAlias[key] = note
+		}
+	}
+
+	return v
+}
+
+func Load(root string, opts ...Option) (*Vault, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, fmt.Errorf("исходная директория не существует: %s", root)
+	}
+
+	var notes []*Note
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.ToLower(filepath.Ext(path)) != ".md" {
+			return nil
+		}
+		note, err := ParseNote(path, root, opts...)
+		if err != nil {
+			return fmt.Errorf("ошибка при разборе %s: %v", path, err)
+		}
+		notes = append(notes, note)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	v := New(root, notes)
+
+	log.WithFields(log.Fields{
+		"dir":   root,
+		"notes": len(v.Notes),
+	}).Debug("Хранилище заметок загружено")
+
+	return v, nil
+}
+
+// Resolve находит заметку, на которую указывает ссылка из заметки from, в том числе по псевдониму из aliases.
+// Возвращает nil для внешних ссылок, вложений и несуществующих заметок
+func (v *Vault) Resolve(from *Note, link Link) *Note {
+	if link.IsExternal() {
+		return nil
+	}
+
+	if link.Wiki {
+		if link.Target == "" {
+			return from
+		}
+		target := filepath.ToSlash(strings.TrimSuffix(link.Target, ".md"))
+		// [[folder/Note]] — путь относительно корня хранилища
+		if note, ok := v.byPath[target+".md"]; ok {
+			return note
+		}
+		candidates := v.byName[strings.ToLower(path.Base(target))]
+		if len(candidates) == 0 {
+			// Имя заметки важнее псевдонима другой заметки
+			return v.byAlias[strings.ToLower(target)]
+		}
+		return candidates[0]
+	}
+
+	if link.Target == "" {
+		return from
+	}
+	if strings.ToLower(path.Ext(link.Target)) != ".md" {
+		return nil
+	}
+	var rel string
+	if strings.HasPrefix(link.Target, "/") {
+		rel = path.Clean(strings.TrimPrefix(link.Target, "/"))
+	} else {
+		rel = path.Join(path.Dir(filepath.ToSlash(from.RelPath)), link.Target)
+	}
+	return v.byPath[rel]
+}
+
+// Backlinks возвращает для каждой заметки число входящих ссылок из других заметок
+func (v *Vault) Backlinks() map[*Note]int {
+	counts := make(map[*Note]int)
+	for _, note := range v.Notes {
+		for _, link := range note.Links {
+			if target := v.Resolve(note, link); target != nil && target != note {
+				counts[target]++
+			}
+		}
+	}
+	return counts
+}
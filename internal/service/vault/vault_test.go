@@ -0,0 +1,63 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Success(t *testing.T) {
+	root, err := os.MkdirTemp("", "vault")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "b.md"), []byte("# B"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "sub", "a.md"), []byte("# A"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "image.png"), []byte("png"), 0644))
+
+	v, err := Load(root)
+	require.NoError(t, err)
+
+	require.Len(t, v.Notes, 2)
+	require.Equal(t, "b.md", v.Notes[0].RelPath)
+	require.Equal(t, filepath.Join("sub", "a.md"), v.Notes[1].RelPath)
+}
+
+func TestLoad_SourceDirDoesNotExist(t *testing.T) {
+	_, err := Load(filepath.Join(os.TempDir(), "vault_does_not_exist"))
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "исходная директория не существует")
+}
+
+func TestVault_Resolve(t *testing.T) {
+	daily := &Note{Name: "2024-12-09", RelPath: "daily/2024-12-09.md"}
+	alpha := &Note{Name: "Alpha", RelPath: "projects/Alpha.md", FrontMatter: &FrontMatter{Aliases: StringList{"Старый проект", "2024-12-09"}}}
+	v := New("", []*Note{daily, alpha})
+
+	testCases := []struct {
+		name     string
+		link     Link
+		expected *Note
+	}{
+		{name: "Вики-ссылка по имени без учёта регистра", link: Link{Target: "alpha", Wiki: true}, expected: alpha},
+		{name: "Вики-ссылка с путём", link: Link{Target: "projects/Alpha", Wiki: true}, expected: alpha},
+		{name: "Вики-ссылка на заголовок текущей заметки", link: Link{Anchor: "Итоги", Wiki: true}, expected: daily},
+		{name: "Относительная Markdown-ссылка", link: Link{Target: "../projects/Alpha.md"}, expected: alpha},
+		{name: "Ссылка от корня хранилища", link: Link{Target: "/projects/Alpha.md"}, expected: alpha},
+		{name: "Псевдоним без учёта регистра", link: Link{Target: "старый проект", Wiki: true}, expected: alpha},
+		{name: "Имя заметки важнее псевдонима", link: Link{Target: "2024-12-09", Wiki: true}, expected: daily},
+		{name: "Несуществующая заметка", link: Link{Target: "Beta", Wiki: true}, expected: nil},
+		{name: "Внешняя ссылка", link: Link{Target: "https://example.com/a.md"}, expected: nil},
+		{name: "Вложение", link: Link{Target: "image.png"}, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, v.Resolve(daily, tc.link))
+		})
+	}
+}
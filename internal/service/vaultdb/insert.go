@@ -0,0 +1,173 @@
+package vaultdb
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+)
+
+func insertNote(tx *sql.Tx, note *vault.Note, html, hash string) (int64, error) {
+	fm := note.FrontMatter
+	if fm == nil {
+		fm = &vault.FrontMatter{}
+	}
+
+	res, err := tx.Exec(`INSERT INTO notes (path, name, title, date, author, closed, word_count, body, html, hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		note.RelPath, note.Name, note.Title(), nullString(fm.Date), nullString(fm.Author),
+		fm.Closed, note.WordCount, string(note.Body), html, hash)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, tag := range note.Tags {
+		if _, err := tx.Exec("INSERT INTO tags (note_id, tag) VALUES (?, ?)", id, tag); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, alias := range fm.Aliases {
+		if _, err := tx.Exec("INSERT INTO aliases (note_id, alias) VALUES (?, ?)", id, alias); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, field := range frontMatterFields(fm) {
+		if err := insertField(tx, id, "frontmatter", field.key, field.value, nil); err != nil {
+			return 0, err
+		}
+	}
+	for _, field := range note.Fields {
+		line := field.Line
+		if err := insertField(tx, id, "inline", field.Key, field.Value, &line); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, task := range note.Tasks {
+		if _, err := tx.Exec(`INSERT INTO tasks (note_id, line, text, status, due, completed, priority)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			id, task.Line, task.Text, task.Status,
+			nullString(task.Due), nullString(task.Completed), nullString(task.Priority)); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, heading := range note.Headings {
+		if _, err := tx.Exec("INSERT INTO headings (note_id, level, text, line) VALUES (?, ?, ?, ?)",
+			id, heading.Level, heading.Text, heading.Line); err != nil {
+			return 0, err
+		}
+	}
+
+	return id, nil
+}
+
+type keyValue struct {
+	key   string
+	value any
+}
+
+// frontMatterFields раскладывает FrontMatter на пары ключ-значение; списки дают по строке на элемент
+func frontMatterFields(fm *vault.FrontMatter) []keyValue {
+	var fields []keyValue
+	if fm.Date != "" {
+		fields = append(fields, keyValue{"date", fm.Date})
+	}
+	if fm.Author != "" {
+		fields = append(fields, keyValue{"author", fm.Author})
+	}
+	for _, tag := range fm.Tags {
+		fields = append(fields, keyValue{"tags", tag})
+	}
+	for _, alias := range fm.Aliases {
+		fields = append(fields, keyValue{"aliases", alias})
+	}
+	fields = append(fields, keyValue{"closed", fm.Closed})
+
+	keys := make([]string, 0, len(fm.Extra))
+	for key := range fm.Extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if list, ok := fm.Extra[key].([]any); ok {
+			for _, item := range list {
+				fields = append(fields, keyValue{key, item})
+			}
+			continue
+		}
+		fields = append(fields, keyValue{key, fm.Extra[key]})
+	}
+	return fields
+}
+
+func insertField(tx *sql.Tx, noteID int64, source, key string, value any, line *int) error {
+	var number any
+	if n, ok := vault.NumericValue(value); ok {
+		number = n
+	}
+	var lineValue any
+	if line != nil {
+		lineValue = *line
+	}
+	_, err := tx.Exec("INSERT INTO fields (note_id, source, key, value, number, line) VALUES (?, ?, ?, ?, ?, ?)",
+		noteID, source, key, vault.FormatValue(value), number, lineValue)
+	return err
+}
+
+// writeSearch добавляет в поисковый индекс заметки, которых в нём ещё нет:
+// новые, перезаписанные и сохранённые до появления индекса
+func writeSearch(tx *sql.Tx) error {
+	_, err := tx.Exec(`INSERT INTO search (rowid, name, title, aliases, tags, body)
+		SELECT id, name, title,
+			(SELECT group_concat(alias, ' ') FROM aliases WHERE note_id = notes.id),
+			(SELECT group_concat(tag, ' ') FROM tags WHERE note_id = notes.id),
+			body
+		FROM notes WHERE id NOT IN (SELECT rowid FROM search)`)
+	if err != nil {
+		return fmt.Errorf("не удалось обновить поисковый индекс: %v", err)
+	}
+	return nil
+}
+
+// writeLinks пересобирает таблицу links и заново разрешает цели ссылок
+func writeLinks(tx *sql.Tx, v *vault.Vault, ids map[*vault.Note]int64) error {
+	if _, err := tx.Exec("DELETE FROM links"); err != nil {
+		return fmt.Errorf("не удалось очистить таблицу links: %v", err)
+	}
+
+	insert, err := tx.Prepare(`INSERT INTO links (note_id, target, anchor, text, line, wiki, embed, target_path)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("не удалось подготовить запрос: %v", err)
+	}
+	defer insert.Close()
+
+	for _, note := range v.Notes {
+		for _, link := range note.Links {
+			var targetPath any
+			if target := v.Resolve(note, link); target != nil {
+				targetPath = target.RelPath
+			}
+			if _, err := insert.Exec(ids[note], link.Target, nullString(link.Anchor), nullString(link.Text),
+				link.Line, link.Wiki, link.Embed, targetPath); err != nil {
+				return fmt.Errorf("не удалось записать ссылки %s: %v", note.RelPath, err)
+			}
+		}
+	}
+	return nil
+}
+
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package vaultdb
+
+// schema описывает таблицы базы. Связанные с заметкой строки удаляются каскадно
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS notes (
+		id INTEGER PRIMARY KEY,
+		path TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		title TEXT NOT NULL,
+		date TEXT,
+		author TEXT,
+		closed INTEGER NOT NULL DEFAULT 0,
+		word_count INTEGER NOT NULL DEFAULT 0,
+		body TEXT NOT NULL,
+		html TEXT NOT NULL,
+		hash TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS tags (
+		note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+		tag TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS aliases (
+		note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+		alias TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS fields (
+		note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+		source TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value TEXT,
+		number REAL,
+		line INTEGER
+	)`,
+	`CREATE TABLE IF NOT EXISTS links (
+		note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+		target TEXT NOT NULL,
+		anchor TEXT,
+		text TEXT,
+		line INTEGER NOT NULL,
+		wiki INTEGER NOT NULL,
+		embed INTEGER NOT NULL,
+		target_path TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS tasks (
+		note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+		line INTEGER NOT NULL,
+		text TEXT NOT NULL,
+		status TEXT NOT NULL,
+		due TEXT,
+		completed TEXT,
+		priority TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS headings (
+		note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+		level INTEGER NOT NULL,
+		text TEXT NOT NULL,
+		line INTEGER NOT NULL
+	)`,
+	// Полнотекстовый поиск по заметкам; rowid совпадает с notes.id.
+	// Виртуальная таблица не поддерживает внешние ключи, поэтому строки удаляет триггер
+	`CREATE VIRTUAL TABLE IF NOT EXISTS search USING fts5(
+		name, title, aliases, tags, body,
+		tokenize = 'unicode61'
+	)`,
+	`CREATE TRIGGER IF NOT EXISTS notes_search_delete AFTER DELETE ON notes BEGIN
+		DELETE FROM search WHERE rowid = old.id;
+	END`,
+	`CREATE INDEX IF NOT EXISTS tags_tag ON tags(tag)`,
+	`CREATE INDEX IF NOT EXISTS aliases_alias ON aliases(alias)`,
+	`CREATE INDEX IF NOT EXISTS fields_key ON fields(key)`,
+	`CREATE INDEX IF NOT EXISTS links_target_path ON links(target_path)`,
+	`CREATE INDEX IF NOT EXISTS tasks_status ON tasks(status)`,
+}
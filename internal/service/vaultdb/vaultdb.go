@@ -0,0 +1,155 @@
+package vaultdb
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+
+	_ "modernc.org/sqlite"
+)
+
+// RenderFunc превращает заметку в HTML, который сохраняется в notes.html
+type RenderFunc func(note *vault.Note) ([]byte, error)
+
+// Stats описывает, что изменилось в базе за один запуск
+type Stats struct {
+	Added     int
+	Updated   int
+	Unchanged int
+	Removed   int
+}
+
+// Sync приводит базу по пути path в соответствие с хранилищем v.
+// Заметки с неизменившимся содержимым не перезаписываются, удалённые из хранилища удаляются.
+// Таблица links пересобирается целиком, потому что target_path зависит от всего хранилища.
+// Таблица search — полнотекстовый индекс FTS5 по имени, заголовку, псевдонимам, тегам и тексту заметок.
+// full принудительно перезаписывает все заметки, например чтобы обновить HTML с запросами Dataview
+func Sync(path string, v *vault.Vault, render RenderFunc, full bool) (*Stats, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть базу SQLite: %v", err)
+	}
+	defer db.Close()
+
+	// Одно соединение, чтобы PRAGMA действовала на транзакцию ниже
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		return nil, fmt.Errorf("не удалось включить внешние ключи: %v", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось начать транзакцию: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range schema {
+		if _, err := tx.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("не удалось создать схему: %v", err)
+		}
+	}
+
+	existing, err := loadHashes(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{}
+	ids := make(map[*vault.Note]int64, len(v.Notes))
+	for _, note := range v.Notes {
+		hash, err := fileHash(note.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		old, ok := existing[note.RelPath]
+		delete(existing, note.RelPath)
+		if ok && old.hash == hash && !full {
+			ids[note] = old.id
+			stats.Unchanged++
+			continue
+		}
+
+		html, err := render(note)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось отрисовать %s: %v", note.RelPath, err)
+		}
+
+		if ok {
+			if _, err := tx.Exec("DELETE FROM notes WHERE id = ?", old.id); err != nil {
+				return nil, fmt.Errorf("не удалось удалить устаревшую запись %s: %v", note.RelPath, err)
+			}
+			stats.Updated++
+		} else {
+			stats.Added++
+		}
+
+		id, err := insertNote(tx, note, string(html), hash)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось записать %s: %v", note.RelPath, err)
+		}
+		ids[note] = id
+	}
+
+	removed := make([]string, 0, len(existing))
+	for relPath := range existing {
+		removed = append(removed, relPath)
+	}
+	sort.Strings(removed)
+	for _, relPath := range removed {
+		if _, err := tx.Exec("DELETE FROM notes WHERE id = ?", existing[relPath].id); err != nil {
+			return nil, fmt.Errorf("не удалось удалить запись %s: %v", relPath, err)
+		}
+		stats.Removed++
+	}
+
+	if err := writeLinks(tx, v, ids); err != nil {
+		return nil, err
+	}
+	if err := writeSearch(tx); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("не удалось сохранить изменения: %v", err)
+	}
+	return stats, nil
+}
+
+type storedNote struct {
+	id   int64
+	hash string
+}
+
+func loadHashes(tx *sql.Tx) (map[string]storedNote, error) {
+	rows, err := tx.Query("SELECT id, path, hash FROM notes")
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать заметки из базы: %v", err)
+	}
+	defer rows.Close()
+
+	notes := make(map[string]storedNote)
+	for rows.Next() {
+		var path string
+		var note storedNote
+		if err := rows.Scan(&note.id, &path, &note.hash); err != nil {
+			return nil, fmt.Errorf("не удалось прочитать заметки из базы: %v", err)
+		}
+		notes[path] = note
+	}
+	return notes, rows.Err()
+}
+
+func fileHash(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("не удалось прочитать файл %s: %v", path, err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,93 @@
+package vaultdb
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ANkulagin/golang_markdown_converter_sb/internal/service/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func renderStub(note *vault.Note) ([]byte, error) {
+	return []byte("<p>" + note.Name + "</p>"), nil
+}
+
+func TestSync(t *testing.T) {
+	dir, err := os.MkdirTemp("", "vaultdb")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	require.NoError(t, os.MkdirAll(src, 0755))
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(src, name), []byte(content), 0644))
+	}
+	write("2024-12-09.md", "---\ndate: 2024-12-09\ntags: [daily]\nmood: 7\n---\n# Понедельник\n- [ ] задача\nsleep:: 7h\n[[idea]] [[missing]] [[мысль]]\n")
+	write("idea.md", "---\naliases: Мысль\n---\nИдея #work\n")
+
+	dbPath := filepath.Join(dir, "vault.db")
+	sync := func() *Stats {
+		v, err := vault.Load(src)
+		require.NoError(t, err)
+		stats, err := Sync(dbPath, v, renderStub, false)
+		require.NoError(t, err)
+		return stats
+	}
+
+	require.Equal(t, &Stats{Added: 2}, sync())
+
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var title, date, html string
+	var words int
+	require.NoError(t, db.QueryRow("SELECT title, date, word_count, html FROM notes WHERE path = '2024-12-09.md'").
+		Scan(&title, &date, &words, &html))
+	require.Equal(t, "Понедельник", title)
+	require.Equal(t, "2024-12-09", date)
+	require.Equal(t, "<p>2024-12-09</p>", html)
+
+	var number float64
+	require.NoError(t, db.QueryRow("SELECT number FROM fields WHERE key = 'sleep' AND source = 'inline'").Scan(&number))
+	require.Equal(t, 7.0, number)
+	require.NoError(t, db.QueryRow("SELECT number FROM fields WHERE key = 'mood' AND source = 'frontmatter'").Scan(&number))
+	require.Equal(t, 7.0, number)
+
+	count := func(query string) int {
+		var n int
+		require.NoError(t, db.QueryRow(query).Scan(&n))
+		return n
+	}
+	require.Equal(t, 1, count("SELECT COUNT(*) FROM tasks WHERE status = 'open'"))
+	require.Equal(t, 1, count("SELECT COUNT(*) FROM headings WHERE level = 1"))
+	require.Equal(t, 1, count("SELECT COUNT(*) FROM tags WHERE tag = '#work'"))
+	require.Equal(t, 1, count("SELECT COUNT(*) FROM aliases WHERE alias = 'Мысль'"))
+	require.Equal(t, 2, count("SELECT COUNT(*) FROM links WHERE target_path = 'idea.md'"))
+	require.Equal(t, 1, count("SELECT COUNT(*) FROM links WHERE target_path IS NULL"))
+
+	var found string
+	require.NoError(t, db.QueryRow("SELECT n.path FROM search JOIN notes n ON n.id = search.rowid WHERE search MATCH 'aliases:мысль'").Scan(&found))
+	require.Equal(t, "idea.md", found)
+	require.Equal(t, 1, count("SELECT COUNT(*) FROM search WHERE search MATCH 'понедельник'"))
+
+	// Повторный запуск без изменений ничего не перезаписывает
+	require.Equal(t, &Stats{Unchanged: 2}, sync())
+
+	// Изменённая заметка перезаписывается, удалённая исчезает вместе со связанными строками,
+	// а ссылка на новую заметку разрешается
+	write("2024-12-09.md", "---\ndate: 2024-12-09\n---\n# Понедельник\n[[missing]]\n")
+	require.NoError(t, os.Remove(filepath.Join(src, "idea.md")))
+	write("missing.md", "Нашлась\n")
+	require.Equal(t, &Stats{Added: 1, Updated: 1, Removed: 1}, sync())
+
+	require.Equal(t, 2, count("SELECT COUNT(*) FROM notes"))
+	require.Equal(t, 0, count("SELECT COUNT(*) FROM tasks"))
+	require.Equal(t, 0, count("SELECT COUNT(*) FROM tags WHERE tag = '#work'"))
+	require.Equal(t, 0, count("SELECT COUNT(*) FROM aliases"))
+	require.Equal(t, 0, count("SELECT COUNT(*) FROM search WHERE search MATCH 'мысль'"))
+	require.Equal(t, 1, count("SELECT COUNT(*) FROM search WHERE search MATCH 'нашлась'"))
+	require.Equal(t, 1, count("SELECT COUNT(*) FROM links WHERE target_path = 'missing.md'"))
+}